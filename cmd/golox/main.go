@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/carlclone/golox/lox"
+)
+
+var (
+	useVM  = flag.Bool("vm", false, "run on the bytecode VM instead of the tree-walking interpreter")
+	disasm = flag.Bool("disasm", false, "print the compiled bytecode before running , needs -vm")
+	diag   = flag.String("diagnostics", "text", "error output , text or json (one diagnostic object per line)")
+	path   = flag.String("path", "", "directories searched for imports after the importing file's own , separated by '"+string(os.PathListSeparator)+"'")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, "usage:golox [-vm] [-disasm] [-diagnostics=text|json] [-path dirs] [script]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if *diag != "text" && *diag != "json" || *disasm && !*useVM {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	opts := lox.Options{VM: *useVM, Disasm: *disasm}
+	if *path != "" {
+		opts.ImportPath = filepath.SplitList(*path)
+	}
+	in := lox.NewInterpreter(opts)
+	if len(args) > 1 {
+		flag.Usage()
+		os.Exit(1)
+	} else if len(args) == 1 {
+		runFile(in, args[0])
+	} else {
+		runPrompt(in)
+	}
+}
+
+func runPrompt(in *lox.Interpreter) {
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			break
+		}
+		line := scanner.Text()
+		v, err := in.EvalFile("", line)
+		if missingSemicolon(err) {
+			// > 1 + 2 shows 3 , the ; goes on a line of its own so that a
+			// trailing // comment can't swallow it
+			if v2, err2 := in.EvalFile("", line+"\n;"); !missingSemicolon(err2) {
+				v, err = v2, err2
+			}
+		}
+		if report(in, err) && v != nil {
+			fmt.Println(lox.Stringify(v))
+		}
+	}
+}
+
+// missingSemicolon tells whether err is only a parse error about a missing ;
+func missingSemicolon(err error) bool {
+	ds := lox.Diagnostics(err)
+	return len(ds) == 1 && ds[0].Code == lox.P_EXPECTED_TOKEN && strings.HasPrefix(ds[0].Message, "expected ';'")
+}
+
+func runFile(in *lox.Interpreter, file string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := in.EvalFile(file, string(data)); !report(in, err) {
+		os.Exit(1)
+	}
+}
+
+// report writes err to stderr in the -diagnostics format , false if there
+// was one
+func report(in *lox.Interpreter, err error) bool {
+	if err == nil {
+		return true
+	}
+	if *diag == "text" {
+		fmt.Fprintln(os.Stderr, in.FormatError(err))
+		return false
+	}
+	enc := json.NewEncoder(os.Stderr)
+	for _, d := range lox.Diagnostics(err) {
+		enc.Encode(d)
+	}
+	return false
+}
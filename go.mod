@@ -0,0 +1,3 @@
+module github.com/carlclone/golox
+
+go 1.21
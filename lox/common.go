@@ -1,10 +1,9 @@
-package main
+package lox
 
-import "fmt"
-
-func errorAt(line int, where string, msg string) string {
-	return fmt.Sprintf("[line %v] error%v: %v", line, where, msg)
-}
+import (
+	"fmt"
+	"unicode"
+)
 
 func isAlpha(ch byte) bool {
 	return (ch >= 'a' && ch <= 'z') ||
@@ -20,14 +19,17 @@ func isAlphaNum(ch byte) bool {
 	return isAlpha(ch) || isDigit(ch)
 }
 
-func errorAtToken(t *tokenObj, msg string) string {
-	var e string
-	if t.tok == EOF {
-		e = errorAt(t.line, " at end", msg)
-	} else {
-		e = errorAt(t.line, " at '"+t.lexeme+"'", msg)
-	}
-	return e
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func isBinDigit(ch byte) bool {
+	return ch == '0' || ch == '1'
+}
+
+// isLetter is isAlpha for any UTF-8 letter , identifiers may be unicode
+func isLetter(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
 }
 
 func printExprAST(e Expr) string {
@@ -35,9 +37,9 @@ func printExprAST(e Expr) string {
 	case *BinaryExpr:
 		return fmt.Sprintf("(%v %v %v)",
 			o.operator.tok, printExprAST(o.left), printExprAST(o.right))
-	//case *TernaryExpr:
-	//	return fmt.Sprintf("(%v %v %v %v)",
-	//		o.operator.tok, printExprAST(o.op1), printExprAST(o.op2), printExprAST(o.op3))
+	case *TernaryExpr:
+		return fmt.Sprintf("(%v %v %v %v)",
+			o.operator.tok, printExprAST(o.op1), printExprAST(o.op2), printExprAST(o.op3))
 	case *UnaryExpr:
 		return fmt.Sprintf("(%v %v)",
 			o.operator.tok, printExprAST(o.right))
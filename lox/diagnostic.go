@@ -0,0 +1,151 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ------------------------------------------
+// diagnostics , the errors of the scanner , parser , resolver and compiler
+//
+// every diagnostic has a stable code so that tools can tell them apart
+// without matching on the message
+
+type Severity uint
+
+const (
+	SEV_ERROR Severity = iota
+	SEV_WARNING
+)
+
+func (s Severity) String() string {
+	if s == SEV_WARNING {
+		return "warning"
+	}
+	return "error"
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// diagnostic codes , L lexical , P parse , S static (resolver) , C compile
+// and R runtime
+const (
+	L_UNEXPECTED_CHAR       = "L001"
+	L_UNTERMINATED_STRING   = "L002"
+	L_UNTERMINATED_COMMENT  = "L003"
+	L_MALFORMED_NUMBER      = "L004"
+	L_INVALID_ESCAPE        = "L005"
+	P_EXPECTED_EXPRESSION   = "P001"
+	P_EXPECTED_TOKEN        = "P002"
+	P_INVALID_ASSIGNMENT    = "P003"
+	P_TOO_MANY_ARGUMENTS    = "P004"
+	P_TOO_MANY_PARAMETERS   = "P005"
+	S_TOP_LEVEL_RETURN      = "S001"
+	S_INITIALIZER_RETURN    = "S002"
+	S_ALREADY_DECLARED      = "S003"
+	S_OWN_INITIALIZER       = "S004"
+	S_THIS_OUTSIDE_CLASS    = "S005"
+	S_SUPER_OUTSIDE_CLASS   = "S006"
+	S_SUPER_NO_SUPERCLASS   = "S007"
+	S_INHERIT_ITSELF        = "S008"
+	S_BREAK_OUTSIDE_LOOP    = "S009"
+	S_CONTINUE_OUTSIDE_LOOP = "S010"
+	S_TOP_LEVEL_YIELD       = "S011"
+	S_INITIALIZER_YIELD     = "S012"
+	C_TOO_MANY_CONSTANTS    = "C001"
+	C_JUMP_TOO_FAR          = "C002"
+	C_LOOP_TOO_LARGE        = "C003"
+	C_TOO_MANY_LOCALS       = "C004"
+	C_TOO_MANY_UPVALUES     = "C005"
+	R_RUNTIME               = "R001"
+)
+
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Span     Span     `json:"span"`
+	Notes    []string `json:"notes,omitempty"`
+
+	where   string // " at 'x'" , for the one line form
+	runtime bool
+}
+
+// diagnosticAt is an error at token t
+func diagnosticAt(t *tokenObj, code, msg string) *Diagnostic {
+	where := " at '" + t.lexeme + "'"
+	if t.tok == EOF {
+		where = " at end"
+	}
+	return &Diagnostic{Severity: SEV_ERROR, Code: code, Message: msg, Span: t.span, where: where}
+}
+
+// note adds a hint shown below the source line
+func (d *Diagnostic) note(format string, args ...interface{}) *Diagnostic {
+	d.Notes = append(d.Notes, fmt.Sprintf(format, args...))
+	return d
+}
+
+// Error is the one line form , like "[line 3] error at 'x': message"
+func (d *Diagnostic) Error() string {
+	kind := d.Severity.String()
+	if d.runtime {
+		if d.Span.Line == 0 {
+			return d.Message // raised by a call from Go , there is no line
+		}
+		kind = "runtime error"
+	}
+	return fmt.Sprintf("[line %v] %v%v: %v", d.Span.Line, kind, d.where, d.Message)
+}
+
+// Diagnostics lists the diagnostics in an error returned by Eval , errors
+// that are not diagnostics get the runtime code
+func Diagnostics(err error) []*Diagnostic {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		ds := []*Diagnostic{}
+		for _, e := range joined.Unwrap() {
+			ds = append(ds, Diagnostics(e)...)
+		}
+		return ds
+	}
+	var d *Diagnostic
+	if errors.As(err, &d) {
+		return []*Diagnostic{d}
+	}
+	return []*Diagnostic{{Severity: SEV_ERROR, Code: R_RUNTIME, Message: err.Error(), runtime: true}}
+}
+
+// FormatError renders an error returned by Eval for display , each
+// diagnostic is followed by the source line it points at and its notes
+//
+//	[line 3] error at ';': expected expression
+//	 --> main.lox:3:9
+//	  |
+//	3 | var x = ;
+//	  |         ^
+func FormatError(err error, source string) string {
+	return formatError(err, func(*Diagnostic) string { return source })
+}
+
+// formatError is FormatError with the source picked per diagnostic
+func formatError(err error, sourceOf func(*Diagnostic) string) string {
+	parts := []string{}
+	for _, d := range Diagnostics(err) {
+		b := &strings.Builder{}
+		b.WriteString(d.Error())
+		if u := d.Span.underline(sourceOf(d)); u != "" {
+			b.WriteString("\n" + u)
+		}
+		for _, n := range d.Notes {
+			b.WriteString("\n  = note: " + n)
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,226 @@
+package lox
+
+type (
+	value interface{} // alias for readability
+
+	Expr interface { // interface declaration for type check
+		//aExpr()
+		eval(*Env) value
+		accept(resolver *Resolver)
+		pos() Span
+		setPos(Span)
+	}
+
+	expr struct {
+		span Span // the whole expression , set by the parser
+	} // think as a parent class
+
+	// resolved is where the resolver found a variable , the zero value is a
+	// global. it lives on the node so nothing outlives the AST
+	resolved struct {
+		depth int // scopes between the use and the declaration
+		local bool
+	}
+
+	GetExpr struct {
+		name   *tokenObj
+		object Expr
+
+		expr
+	}
+
+	SetExpr struct {
+		name    *tokenObj
+		object  Expr
+		vlue    Expr      // nil for ++ and --
+		op      *tokenObj // += -= *= /= %= ++ -- , nil for =
+		postfix bool      // x.y++ , produces the old value
+
+		expr
+	}
+
+	IndexGetExpr struct { // xs[i]
+		object  Expr
+		bracket *tokenObj // for error display
+		index   Expr
+
+		expr
+	}
+
+	IndexSetExpr struct { // xs[i] = v
+		object  Expr
+		bracket *tokenObj
+		index   Expr
+		value   Expr      // nil for ++ and --
+		op      *tokenObj // like SetExpr
+		postfix bool
+
+		expr
+	}
+
+	ListExpr struct { // [1, 2, 3]
+		bracket  *tokenObj
+		elements []Expr
+		expr
+	}
+
+	MapExpr struct { // {"k": 1, "j": 2}
+		brace  *tokenObj
+		keys   []Expr
+		values []Expr
+		expr
+	}
+
+	AssignExpr struct {
+		name    *tokenObj
+		value   Expr      // nil for ++ and --
+		op      *tokenObj // like SetExpr
+		postfix bool
+		at      resolved
+		expr    //extend parent class and its method
+	}
+
+	BinaryExpr struct {
+		operator    *tokenObj
+		left, right Expr
+		expr
+	}
+
+	CallExpr struct { // asd(1,2) , no env related struct
+		callee Expr
+		paren  *tokenObj
+		args   []Expr
+		expr
+	}
+
+	FunExpr struct { //fun decl , difference between FunAnon , FunObj , no env related
+		params    []*tokenObj
+		body      []Stmt
+		generator bool // contains a yield , set by the resolver
+		expr
+	}
+
+	GroupingExpr struct {
+		expression Expr //mostly expression surround by ()
+		// design for general method , like extend in oop
+		expr
+	}
+
+	LiteralExpr struct {
+		value interface{}
+		expr
+	}
+
+	InterpolationExpr struct { // "a ${x} b" , parts are the segments and the embedded expressions
+		parts []Expr
+		expr
+	}
+
+	LogicalExpr struct {
+		operator    *tokenObj
+		left, right Expr
+		expr
+	}
+
+	TernaryExpr struct { // op1 ? op2 : op3
+		operator      *tokenObj // the ?
+		op1, op2, op3 Expr
+		expr
+	}
+
+	UnaryExpr struct {
+		operator *tokenObj
+		right    Expr
+		expr
+	}
+
+	VarExpr struct {
+		name *tokenObj
+		at   resolved
+		expr
+	}
+
+	ThisExpr struct {
+		keyword *tokenObj
+		at      resolved
+		expr
+	}
+
+	SuperExpr struct { // super.method , always followed by a method name
+		keyword *tokenObj
+		method  *tokenObj
+		at      resolved
+		expr
+	}
+)
+
+//func (*expr) aExpr()                    {} //add a empty method to distinct with other interface that has eval
+func (*expr) eval(*Env) value           { return nil }
+func (*expr) accept(resolver *Resolver) {}
+func (e *expr) pos() Span               { return e.span }
+func (e *expr) setPos(s Span)           { e.span = s }
+
+func (s *VarExpr) accept(r *Resolver) {
+	r.visitVariableExpr(s)
+}
+
+func (s *UnaryExpr) accept(r *Resolver) {
+	r.visitUnaryExpr(s)
+}
+func (s *LogicalExpr) accept(r *Resolver) {
+	r.visitLogicalExpr(s)
+}
+func (s *TernaryExpr) accept(r *Resolver) {
+	r.visitTernaryExpr(s)
+}
+func (s *LiteralExpr) accept(r *Resolver) {
+	r.visitLiteralExpr(s)
+}
+func (s *InterpolationExpr) accept(r *Resolver) {
+	r.visitInterpolationExpr(s)
+}
+func (s *GroupingExpr) accept(r *Resolver) {
+	r.visitGroupingExpr(s)
+}
+func (s *FunExpr) accept(r *Resolver) {
+	r.visitFunExpr(s)
+}
+func (s *CallExpr) accept(r *Resolver) {
+	r.visitCallExpr(s)
+}
+func (s *BinaryExpr) accept(r *Resolver) {
+	r.visitBinaryExpr(s)
+}
+func (s *AssignExpr) accept(r *Resolver) {
+	r.visitAssignExpr(s)
+}
+
+func (s *ThisExpr) accept(r *Resolver) {
+	r.visitThisExpr(s)
+}
+func (s *SuperExpr) accept(r *Resolver) {
+	r.visitSuperExpr(s)
+}
+func (s *SetExpr) accept(r *Resolver) {
+	r.visitSetExpr(s)
+}
+
+func (s *GetExpr) accept(r *Resolver) {
+	r.visitGetExpr(s)
+}
+
+func (s *IndexGetExpr) accept(r *Resolver) {
+	r.visitIndexGetExpr(s)
+}
+
+func (s *IndexSetExpr) accept(r *Resolver) {
+	r.visitIndexSetExpr(s)
+}
+
+func (s *ListExpr) accept(r *Resolver) {
+	r.visitListExpr(s)
+}
+
+func (s *MapExpr) accept(r *Resolver) {
+	r.visitMapExpr(s)
+}
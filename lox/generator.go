@@ -0,0 +1,167 @@
+package lox
+
+import (
+	"runtime"
+)
+
+// ------------------------------------------
+// generators , a function with a yield in its body
+//
+// calling it binds the arguments and returns a generator without running the
+// body. every next() runs the body on to the following yield and returns the
+// yielded value , or nil once the body has returned. for (x in gen()) goes
+// until the body returns , so a yielded nil doesn't end the loop
+
+type LoxGenerator struct {
+	name    string
+	resume  func(t *tokenObj) (value, bool) // false when the body returned
+	running bool
+	done    bool
+}
+
+func (g *LoxGenerator) String() string {
+	return stringify(g)
+}
+
+// next resumes the body , t is where the value is asked for
+func (g *LoxGenerator) next(t *tokenObj) (value, bool) {
+	if g.running {
+		runtimeErr(t, "generator is already running")
+	}
+	if g.done {
+		return nil, false
+	}
+	g.running = true
+	defer func() { g.running = false }()
+	g.done = true // until the body got to a yield , an error ends it too
+	v, ok := g.resume(t)
+	g.done = !ok
+	return v, ok
+}
+
+func (g *LoxGenerator) get(name *tokenObj) value {
+	switch name.lexeme {
+	case "next":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			v, _ := g.next(name)
+			return v
+		}}
+	case "iter":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			return g
+		}}
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
+
+// ------------------------------------------
+// tree-walker , the body runs on a goroutine of its own and keeps its Env and
+// Go stack between resumes. the resumer and the body hand over through
+// channels so only one of them runs at a time
+
+type treeCoroutine struct {
+	resume chan bool // false or closed asks the body to stop
+	yield  chan genResult
+}
+
+type genResult struct {
+	v     value
+	done  bool
+	panic interface{} // raised by the body , raised again by the resumer
+}
+
+// genExit unwinds the goroutine of a generator nobody can resume anymore
+type genExit struct{}
+
+// newTreeGenerator makes the generator of a call , env has the arguments
+func newTreeGenerator(name string, body []Stmt, env *Env) *LoxGenerator {
+	co := &treeCoroutine{resume: make(chan bool), yield: make(chan genResult)}
+	env.co = co
+	lim := env.globals.interp.limits
+	started := false
+	g := &LoxGenerator{name: name, resume: func(t *tokenObj) (value, bool) {
+		if !started {
+			started = true
+			go co.run(body, env)
+		}
+		lim.enter(t)
+		co.resume <- true
+		r := <-co.yield
+		if r.panic != nil {
+			panic(r.panic)
+		}
+		lim.leave()
+		return r.v, !r.done
+	}}
+	// the finalizer must not refer to g , or g is never collected
+	runtime.SetFinalizer(g, func(*LoxGenerator) {
+		if started {
+			close(co.resume)
+		}
+	})
+	return g
+}
+
+func (co *treeCoroutine) run(body []Stmt, env *Env) {
+	defer func() {
+		if e := recover(); e != nil {
+			if _, ok := e.(genExit); !ok {
+				co.yield <- genResult{panic: e}
+			}
+			return
+		}
+		co.yield <- genResult{done: true}
+	}()
+	co.wait()
+	execBlock(body, env)
+}
+
+// wait blocks the body until the next resume
+func (co *treeCoroutine) wait() {
+	if !<-co.resume {
+		panic(genExit{})
+	}
+}
+
+// coroutine is the generator a yield in env belongs to
+func (e *Env) coroutine() *treeCoroutine {
+	for e.co == nil {
+		e = e.enclosing
+	}
+	return e.co
+}
+
+// ------------------------------------------
+// VM , every generator has a VM of its own whose only frame is the body ,
+// OP_YIELD returns from its dispatch with the value on top and the frame
+// left to resume
+
+func (vm *VM) newGenerator(closure *vmClosure, slots []value) *LoxGenerator {
+	co := &VM{
+		stack:  append(make([]value, 0, 64), slots...),
+		frames: []callFrame{{closure: closure}},
+		stdout: vm.stdout,
+		limits: vm.limits,
+		interp: vm.interp,
+	}
+	return &LoxGenerator{name: closure.fn.name, resume: func(*tokenObj) (value, bool) {
+		return co.resume()
+	}}
+}
+
+// resume runs the generator VM up to its next yield , nested in the VM
+// running now so that the stack depth adds up
+func (co *VM) resume() (value, bool) {
+	in := co.interp
+	parent := in.running
+	co.depthBase = parent.depthBase + len(parent.frames)
+	in.running = co
+	defer func() { in.running = parent }()
+	co.run(0)
+	v := co.pop()
+	if len(co.frames) == 0 {
+		return nil, false // returned , the value is dropped
+	}
+	return v, true
+}
@@ -0,0 +1,461 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuntimeError is the diagnostic of an error raised while running
+type RuntimeError struct {
+	*Diagnostic
+	value  value // what throw threw , when thrown is set
+	thrown bool
+	fatal  bool // a limit was hit , try can't catch it
+}
+
+func (e RuntimeError) Unwrap() error {
+	return e.Diagnostic
+}
+
+// newRuntimeError makes a RuntimeError at span , the zero Span when the
+// error is raised by a call from Go
+func newRuntimeError(span Span, msg string) RuntimeError {
+	return RuntimeError{Diagnostic: &Diagnostic{Severity: SEV_ERROR, Code: R_RUNTIME, Message: msg, Span: span, runtime: true}}
+}
+
+//helper
+func runtimeErr(t *tokenObj, msg string) error {
+	panic(newRuntimeError(t.span, msg))
+}
+
+// stringify formats v the way print shows it , it is shared by both backends
+// and by + and str() , the String methods of the values call it too
+//
+//	nil true 3 2.5 1e+21 <fn name> <class Name> Name instance [1, nil] {a: 1}
+func stringify(v value) string {
+	switch o := v.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(o)
+	case int64:
+		return strconv.FormatInt(o, 10)
+	case float64:
+		return formatNumber(o)
+	case string:
+		return o
+	case *FunObj:
+		return "<fn " + o.decl.name.lexeme + ">"
+	case *FunAnon:
+		return "<fn>"
+	case *vmFunction:
+		if o.name == "" {
+			return "<fn>"
+		}
+		return "<fn " + o.name + ">"
+	case *vmClosure:
+		return stringify(o.fn)
+	case *vmBoundMethod:
+		return stringify(o.method)
+	case *goFunc:
+		return "<native fn " + o.name + ">"
+	case *nativeMethod:
+		return "<native fn " + o.name + ">"
+	case *LoxClass:
+		return "<class " + o.name + ">"
+	case *vmClass:
+		return "<class " + o.name + ">"
+	case *LoxError:
+		return o.err.Message
+	case *LoxModule:
+		return "<module " + o.name + ">"
+	case *LoxGenerator:
+		if o.name == "" {
+			return "<generator>"
+		}
+		return "<generator " + o.name + ">"
+	case *LoxRange:
+		return fmt.Sprintf("range(%v, %v, %v)", o.start, o.end, o.step)
+	case *LoxInstance:
+		return o.klass.name + " instance"
+	case *vmInstance:
+		return o.klass.name + " instance"
+	case *LoxList, *LoxMap:
+		return stringifyNested(o, make(map[value]bool))
+	case Callable:
+		return "<native fn>"
+	}
+	return fmt.Sprintf("%v", v) // values registered from Go
+}
+
+// stringifyNested formats a list or map , seen holds the ones being formatted
+// around it so that a container holding itself prints as [...] or {...}
+// instead of recursing forever
+func stringifyNested(v value, seen map[value]bool) string {
+	elem := func(e value) string {
+		switch e.(type) {
+		case *LoxList, *LoxMap:
+			return stringifyNested(e, seen)
+		}
+		return stringify(e)
+	}
+	switch o := v.(type) {
+	case *LoxList:
+		if seen[o] {
+			return "[...]"
+		}
+		seen[o] = true
+		defer delete(seen, o)
+		s := make([]string, 0, len(o.elements))
+		for _, e := range o.elements {
+			s = append(s, elem(e))
+		}
+		return "[" + strings.Join(s, ", ") + "]"
+	case *LoxMap:
+		if seen[o] {
+			return "{...}"
+		}
+		seen[o] = true
+		defer delete(seen, o)
+		s := make([]string, 0, len(o.order))
+		for _, k := range o.order {
+			s = append(s, elem(k)+": "+elem(o.entries[k]))
+		}
+		return "{" + strings.Join(s, ", ") + "}"
+	}
+	return stringify(v)
+}
+
+// add is + for both backends , numbers are summed and a string on either
+// side joins with the other value stringified , the string is the error
+// message when it fails
+func add(x, y value) (value, string) {
+	xs, xok := x.(string)
+	ys, yok := y.(string)
+	switch {
+	case xok && yok:
+		return xs + ys, ""
+	case xok:
+		return xs + stringify(y), ""
+	case yok:
+		return stringify(x) + ys, ""
+	}
+	if isNumber(x) && isNumber(y) {
+		return arith(Plus, x, y)
+	}
+	return nil, "operands must be two numbers or include a string"
+}
+
+var compoundOps = map[token]token{
+	MinusEqual:   Minus,
+	StarEqual:    Star,
+	SlashEqual:   Slash,
+	PercentEqual: Percent,
+}
+
+// compound is the value x op= y stores for both backends , or x++ and x--
+// which have no y and only take numbers
+func compound(op token, x, y value) (value, string) {
+	switch op {
+	case PlusPlus, MinusMinus:
+		if !isNumber(x) {
+			return nil, "operand must be a number"
+		}
+		if op == PlusPlus {
+			return arith(Plus, x, int64(1))
+		}
+		return arith(Minus, x, int64(1))
+	case PlusEqual:
+		return add(x, y)
+	}
+	return arith(compoundOps[op], x, y)
+}
+
+// formatNumber prints integers without .0 , large and tiny numbers with an
+// exponent
+func formatNumber(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case f == math.Trunc(f) && math.Abs(f) < 1e21:
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type CompletionType uint
+
+const (
+	CP_NORMAL = iota
+	CP_RETURN
+	CP_BREAK
+	CP_CONTINUE
+)
+
+// Completion tells the enclosing statement how a statement finished ,
+// return/break/continue travel up as plain values instead of panics
+type Completion struct {
+	kind  CompletionType
+	value value // return value
+}
+
+type Callable interface {
+	arity() int //arity 参数个数
+	call(*Env, []value) value
+}
+
+// ------------------------------------------
+// env
+
+// env contains bindings for variables.
+type Env struct {
+	values map[string]value // decl but not inited
+
+	// init means that variable was properly initialized
+	init map[string]bool
+
+	enclosing *Env // most close upper env
+	globals   *Env // always points to the root of enclosures
+
+	interp *Interpreter // owner , only set on the root env
+
+	co *treeCoroutine // set on the env of a generator call
+}
+
+func NewEnv(enclosing *Env) *Env {
+	e := &Env{values: make(map[string]value), init: make(map[string]bool), enclosing: enclosing}
+	if enclosing == nil {
+		// means that this created env is the root, that is global env
+		e.globals = e
+	} else {
+		e.globals = enclosing.globals
+	}
+	return e
+}
+
+func (e *Env) defineInit(name string, v value) {
+	e.values[name] = v
+	e.init[name] = true
+}
+
+func (e *Env) define(name string) {
+	e.values[name] = nil
+}
+
+func (e *Env) ancestor(distance int) *Env {
+	var env *Env
+	env = e
+	for i := 0; i < distance; i++ {
+		env = env.enclosing
+	}
+	return env
+}
+
+func (e *Env) getAt(distance int, name string) value {
+	return e.ancestor(distance).values[name]
+}
+
+func (e *Env) assignAt(distance int, name *tokenObj, v value) {
+	e.ancestor(distance).values[name.lexeme] = v //todo;why lexeme instead of literal
+}
+
+func (e *Env) lookUpVariable(name *tokenObj, at resolved) (v value) {
+	if at.local {
+		v = e.getAt(at.depth, name.lexeme)
+	} else {
+		v = e.globals.get(name)
+	}
+	return v
+}
+
+//todo ; should be deprecate
+func (e *Env) get(name *tokenObj) value {
+	if v, ok := e.values[name.lexeme]; ok {
+		if _, ok := e.init[name.lexeme]; !ok {
+			runtimeErr(name, "variable '"+name.lexeme+"' should be initialized first")
+		}
+		return v
+	}
+	if e.enclosing != nil {
+		return e.enclosing.get(name)
+	}
+
+	runtimeErr(name, "undefined variable '"+name.lexeme+"'")
+	return nil
+}
+
+//
+func (e *Env) assign(name *tokenObj, v value) {
+	if _, ok := e.values[name.lexeme]; ok {
+		e.values[name.lexeme] = v
+		e.init[name.lexeme] = true
+		return
+	}
+	if e.enclosing != nil {
+		e.enclosing.assign(name, v)
+		return
+	}
+
+	runtimeErr(name, "undefined variable '"+name.lexeme+"'")
+	return
+}
+
+// ------------------------------------------
+// interpret
+
+// interpret runs stmt in env , the value of a trailing expression statement
+// is returned
+func interpret(stmt []Stmt, env *Env) (v value, err error) {
+	//handle panic and output , all kinds of interpret err
+	defer func() {
+		if e := recover(); e != nil {
+			re, ok := e.(RuntimeError)
+			if !ok {
+				panic(e) // a bug in the interpreter itself
+			}
+			err = re
+		}
+	}()
+	for i, s := range stmt {
+		if es, ok := s.(*ExprStmt); ok && i == len(stmt)-1 {
+			return es.expression.eval(env), nil
+		}
+		if c := s.execute(env); c.kind != CP_NORMAL {
+			break // return at top level ends the program
+		}
+	}
+	return nil, nil
+}
+
+// callFunction calls fn from Go , see Interpreter.Call
+func callFunction(env *Env, fn value, args []value) (v value, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			re, ok := e.(RuntimeError)
+			if !ok {
+				panic(e)
+			}
+			err = re
+		}
+	}()
+	if native, ok := fn.(*goFunc); ok {
+		return native.callAt(nil, args), nil
+	}
+	callee, ok := fn.(Callable)
+	if !ok {
+		return nil, newRuntimeError(Span{}, fmt.Sprintf("'%v' is not a function or class", stringify(fn)))
+	}
+	if len(args) != callee.arity() {
+		return nil, newRuntimeError(Span{}, fmt.Sprintf("expected %v arguments but got %v", callee.arity(), len(args)))
+	}
+	return callee.call(env, args), nil
+}
+
+// ------------------------------------------
+// clockFn is a closure variable
+
+type clockFn struct{}
+
+func (c clockFn) arity() int {
+	return 0
+}
+
+func (c clockFn) call(_ *Env, _ []value) value { // does not care about args , but to obey the interface standard
+	return float64(time.Now().UnixNano())
+}
+
+// strFn is str(v) , the value as print shows it
+type strFn struct{}
+
+func (s strFn) arity() int {
+	return 1
+}
+
+func (s strFn) call(_ *Env, args []value) value {
+	return stringify(args[0])
+}
+
+// ------------------------------------------
+// Function
+// FunObj
+
+type FunObj struct {
+	decl          *FunStmt
+	closure       *Env
+	isInitializer bool
+}
+
+func (f *FunObj) bind(l *LoxInstance) *FunObj {
+	env := NewEnv(f.closure)
+	env.defineInit("this", l)
+	return &FunObj{
+		decl:          f.decl,
+		closure:       env,
+		isInitializer: f.isInitializer,
+	}
+}
+
+func (f *FunObj) arity() int {
+	return len(f.decl.params)
+}
+
+//LoxFunction
+func (f *FunObj) call(e *Env, args []value) value {
+	env := NewEnv(f.closure)          //create an env for function call
+	for i, p := range f.decl.params { //args adds into env
+		env.defineInit(p.lexeme, args[i])
+	}
+
+	if f.decl.generator {
+		return newTreeGenerator(f.decl.name.lexeme, f.decl.body, env)
+	}
+	c := execBlock(f.decl.body, env) //exec the func body with its env
+	if f.isInitializer {
+		return f.closure.getAt(0, "this") // init() always returns this
+	}
+	if c.kind == CP_RETURN {
+		return c.value
+	}
+	return nil
+}
+
+func (f *FunObj) String() string {
+	return stringify(f)
+}
+
+// closure , anonymous function
+type FunAnon struct {
+	decl    *FunExpr
+	closure *Env
+}
+
+func (f *FunAnon) arity() int {
+	return len(f.decl.params)
+}
+
+func (f *FunAnon) call(e *Env, args []value) value {
+	// Should it use env that is passed by expression?
+	env := NewEnv(f.closure) // only difference between function
+	for i, p := range f.decl.params {
+		env.defineInit(p.lexeme, args[i])
+	}
+
+	if f.decl.generator {
+		return newTreeGenerator("", f.decl.body, env)
+	}
+	if c := execBlock(f.decl.body, env); c.kind == CP_RETURN {
+		return c.value
+	}
+	return nil
+}
+
+func (f *FunAnon) String() string {
+	return stringify(f)
+}
@@ -0,0 +1,48 @@
+package lox
+
+// ------------------------------------------
+// Error , what catch binds for an error raised by the runtime itself
+//
+// throw can throw any value , catch gets it back unchanged. errors like
+// division by zero are caught as an Error with e.message and e.line , and
+// throwing that Error again raises the original error
+
+type LoxError struct {
+	err RuntimeError
+}
+
+func (e *LoxError) String() string {
+	return stringify(e)
+}
+
+func (e *LoxError) get(name *tokenObj) value {
+	switch name.lexeme {
+	case "message":
+		return e.err.Message
+	case "line":
+		return int64(e.err.Span.Line)
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
+
+// catchValue is the value catch binds for err , false when try can't catch it
+func catchValue(err RuntimeError) (value, bool) {
+	if err.fatal {
+		return nil, false
+	}
+	if err.thrown {
+		return err.value, true
+	}
+	return &LoxError{err: err}, true
+}
+
+// throwError is the error raised by throw v at span
+func throwError(span Span, v value) RuntimeError {
+	if e, ok := v.(*LoxError); ok {
+		return e.err
+	}
+	err := newRuntimeError(span, "uncaught exception: "+stringify(v))
+	err.value, err.thrown = v, true
+	return err
+}
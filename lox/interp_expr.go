@@ -0,0 +1,323 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ------------------------------------------
+// Expression Eval
+
+func (e *BinaryExpr) eval(env *Env) value {
+	switch e.operator.tok {
+	case Plus:
+		v, msg := add(e.left.eval(env), e.right.eval(env))
+		if msg != "" {
+			runtimeErr(e.operator, msg)
+		}
+		return v
+	case Minus, Slash, Div, Star, StarStar, Percent, Amp, Pipe, Caret, LessLess, GreaterGreater,
+		Greater, GreaterEqual, Less, LessEqual:
+		v, msg := arith(e.operator.tok, e.left.eval(env), e.right.eval(env))
+		if msg != "" {
+			runtimeErr(e.operator, msg)
+		}
+		return v
+	case EqualEqual:
+		return e.equal(env)
+	case BangEqual:
+		return !e.equal(env)
+	}
+	return nil // Unreachable?
+}
+
+func (e *BinaryExpr) equal(env *Env) bool {
+	return valuesEqual(e.left.eval(env), e.right.eval(env))
+}
+
+//TODO; something that is callable ? , nested call ?
+func (e *CallExpr) eval(env *Env) value {
+	callee := e.callee.eval(env)
+	args := make([]value, 0)
+	for _, a := range e.args {
+		args = append(args, a.eval(env))
+	}
+	if fn, ok := callee.(*goFunc); ok {
+		// counted as a call , it may call back into Lox
+		lim := env.globals.interp.limits
+		lim.enter(e.paren)
+		v := fn.callAt(e.paren, args) // checks its own arity , may be variadic
+		lim.leave()
+		return v
+	}
+	if fn, ok := callee.(Callable); ok {
+		if len(args) != fn.arity() {
+			runtimeErr(e.paren,
+				fmt.Sprintf("expected %v arguments but got %v", fn.arity(), len(args)))
+		}
+		lim := env.globals.interp.limits
+		lim.enter(e.paren)
+		v := fn.call(env, args)
+		lim.leave()
+		return v
+	} else {
+		err := fmt.Sprintf("'%v' is not a function or class", stringify(callee))
+		runtimeErr(e.paren, err)
+		return nil
+	}
+}
+
+// closure produce/eval a FunAnon object which is able to be execute
+func (s *FunExpr) eval(env *Env) value {
+	fn := &FunAnon{decl: s, closure: env}
+	return fn
+}
+
+// TODO; () ?
+func (e *GroupingExpr) eval(env *Env) value {
+	return e.expression.eval(env)
+}
+
+func (e *InterpolationExpr) eval(env *Env) value {
+	b := &strings.Builder{}
+	for _, part := range e.parts {
+		b.WriteString(stringify(part.eval(env)))
+	}
+	return b.String()
+}
+
+// like string or number , produce itself
+func (e *LiteralExpr) eval(env *Env) value {
+	return e.value
+}
+
+func (e *LogicalExpr) eval(env *Env) value {
+	left := e.left.eval(env)
+	if e.operator.tok == Or {
+		if isTruthy(left) {
+			return left
+		}
+	} else {
+		if !isTruthy(left) {
+			return left
+		}
+	}
+	return e.right.eval(env) //!false
+}
+
+// only the chosen branch is evaluated
+func (e *TernaryExpr) eval(env *Env) value {
+	if isTruthy(e.op1.eval(env)) {
+		return e.op2.eval(env)
+	}
+	return e.op3.eval(env)
+}
+
+func (e *GetExpr) eval(env *Env) value {
+	object := e.object.eval(env)
+	if o, ok := object.(*LoxInstance); ok {
+		return o.get(e.name)
+	}
+	if o, ok := object.(*LoxList); ok {
+		return o.get(e.name)
+	}
+	if o, ok := object.(*LoxMap); ok {
+		return o.get(e.name)
+	}
+	if o, ok := object.(string); ok {
+		return stringGet(o, e.name)
+	}
+	if o, ok := object.(*LoxError); ok {
+		return o.get(e.name)
+	}
+	if o, ok := object.(*LoxModule); ok {
+		return o.get(e.name)
+	}
+	if o, ok := object.(*LoxGenerator); ok {
+		return o.get(e.name)
+	}
+	runtimeErr(e.name, "Only instance have properties")
+	return nil
+}
+
+func (e *SetExpr) eval(env *Env) value {
+	obj := e.object.eval(env)
+
+	if o, ok := obj.(*LoxInstance); ok {
+		if e.op != nil {
+			v, result := update(env, e.op, o.get(e.name), e.vlue, e.postfix)
+			o.set(e.name, v)
+			return result
+		}
+		vlue := e.vlue.eval(env)
+		o.set(e.name, vlue)
+		return vlue
+	}
+	runtimeErr(e.name, "Only instances have fields.")
+	return nil
+}
+
+// update works out a compound assignment or ++ and -- , given the old value
+// of the target it returns the new value to store and the value of the
+// expression , which is the old one for x++
+func update(env *Env, op *tokenObj, old value, rhs Expr, postfix bool) (value, value) {
+	var y value
+	if rhs != nil {
+		y = rhs.eval(env)
+	}
+	v, msg := compound(op.tok, old, y)
+	if msg != "" {
+		runtimeErr(op, msg)
+	}
+	if postfix {
+		return v, old
+	}
+	return v, v
+}
+
+func (e *ListExpr) eval(env *Env) value {
+	elements := make([]value, 0, len(e.elements))
+	for _, el := range e.elements {
+		elements = append(elements, el.eval(env))
+	}
+	return &LoxList{elements: elements}
+}
+
+func (e *MapExpr) eval(env *Env) value {
+	m := NewLoxMap()
+	for i := range e.keys {
+		k := e.keys[i].eval(env)
+		m.setAt(e.brace, k, e.values[i].eval(env))
+	}
+	return m
+}
+
+func (e *IndexGetExpr) eval(env *Env) value {
+	object := e.object.eval(env)
+	index := e.index.eval(env)
+	switch o := object.(type) {
+	case *LoxList:
+		return o.getAt(e.bracket, index)
+	case *LoxMap:
+		return o.getAt(e.bracket, index)
+	case string:
+		return stringAt(e.bracket, o, index)
+	}
+	runtimeErr(e.bracket, "Only lists, maps and strings can be indexed.")
+	return nil
+}
+
+func (e *IndexSetExpr) eval(env *Env) value {
+	object := e.object.eval(env)
+	index := e.index.eval(env)
+	switch o := object.(type) {
+	case *LoxList:
+		if e.op != nil {
+			v, result := update(env, e.op, o.getAt(e.bracket, index), e.value, e.postfix)
+			o.setAt(e.bracket, index, v)
+			return result
+		}
+		v := e.value.eval(env)
+		o.setAt(e.bracket, index, v)
+		return v
+	case *LoxMap:
+		if e.op != nil {
+			v, result := update(env, e.op, o.getAt(e.bracket, index), e.value, e.postfix)
+			o.setAt(e.bracket, index, v)
+			return result
+		}
+		v := e.value.eval(env)
+		o.setAt(e.bracket, index, v)
+		return v
+	}
+	runtimeErr(e.bracket, "Only lists and maps can be indexed.")
+	return nil
+}
+
+func (e *ThisExpr) eval(env *Env) value {
+	return env.lookUpVariable(e.keyword, e.at)
+}
+
+// super.method , bound to the current "this"
+func (e *SuperExpr) eval(env *Env) value {
+	distance := e.at.depth
+	superClass := env.getAt(distance, "super").(*LoxClass)
+	// "this" is always one env nearer than "super"
+	object := env.getAt(distance-1, "this").(*LoxInstance)
+
+	method := superClass.findMethod(e.method.lexeme)
+	if method == nil {
+		runtimeErr(e.method, "Undefined property '"+e.method.lexeme+"'.")
+	}
+	return method.bind(object)
+}
+
+func (e *UnaryExpr) eval(env *Env) value {
+	val := e.right.eval(env)
+	switch e.operator.tok {
+	case Minus, Tilde:
+		v, msg := negate(val)
+		if e.operator.tok == Tilde {
+			v, msg = complement(val)
+		}
+		if msg != "" {
+			runtimeErr(e.operator, msg)
+		}
+		return v
+	case Bang:
+		return !isTruthy(val)
+	}
+	// unreachable?
+	return nil
+}
+
+// produce variable name
+func (e *VarExpr) eval(env *Env) value {
+	return env.lookUpVariable(e.name, e.at)
+	//return env.get(e.name)
+}
+
+func (e *AssignExpr) eval(env *Env) value {
+	/*
+
+		public Object visitAssignExpr(Expr.Assign expr) {
+		    Object value = evaluate(expr.value);
+		lox/Interpreter.java
+		in visitAssignExpr()
+		replace 1 line
+
+		    Integer distance = locals.get(expr);
+		    if (distance != null) {
+		      environment.assignAt(distance, expr.name, value);
+		    } else {
+		      globals.assign(expr.name, value);
+		    }
+
+		    return value;
+	*/
+	var v, result value
+	if e.op != nil {
+		v, result = update(env, e.op, env.lookUpVariable(e.name, e.at), e.value, e.postfix)
+	} else {
+		v = e.value.eval(env)
+		result = v
+	}
+	if e.at.local {
+		env.assignAt(e.at.depth, e.name, v)
+	} else {
+		env.globals.assign(e.name, v)
+	}
+	//env.assign(e.name, v)
+	return result
+}
+
+// false and nil are the only falsey values
+func isTruthy(v value) bool {
+	if v == nil {
+		return false
+	}
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package lox
+
+import (
+	"fmt"
+)
+
+// ------------------------------------------
+// List , the dynamic array value produced by [1, 2, 3]
+
+type LoxList struct {
+	elements []value
+}
+
+func (l *LoxList) String() string {
+	return stringify(l)
+}
+
+// index checks v is a valid position in [0, bound) , t is used for error display
+func (l *LoxList) index(t *tokenObj, v value, bound int) int {
+	return checkIndex(t, "list", v, bound, len(l.elements))
+}
+
+// checkIndex is index for any kind of sequence of length n
+func checkIndex(t *tokenObj, kind string, v value, bound, n int) int {
+	if !isNumber(v) {
+		runtimeErr(t, kind+" index must be a number")
+	}
+	i, ok := toInt(v)
+	if !ok {
+		runtimeErr(t, fmt.Sprintf("%v index must be an integer, got %v", kind, stringify(v)))
+	}
+	if i < 0 {
+		runtimeErr(t, fmt.Sprintf("negative %v index %v", kind, i))
+	}
+	if i >= int64(bound) {
+		runtimeErr(t, fmt.Sprintf("%v index %v out of range for length %v", kind, i, n))
+	}
+	return int(i)
+}
+
+func (l *LoxList) getAt(t *tokenObj, i value) value {
+	return l.elements[l.index(t, i, len(l.elements))]
+}
+
+func (l *LoxList) setAt(t *tokenObj, i value, v value) {
+	l.elements[l.index(t, i, len(l.elements))] = v
+}
+
+// get returns the built-in method bound to this list , xs.push
+func (l *LoxList) get(name *tokenObj) value {
+	switch name.lexeme {
+	case "push":
+		return &nativeMethod{name: name.lexeme, n: 1, fn: func(args []value) value {
+			l.elements = append(l.elements, args[0])
+			return nil
+		}}
+	case "pop":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			if len(l.elements) == 0 {
+				runtimeErr(name, "pop from empty list")
+			}
+			last := l.elements[len(l.elements)-1]
+			l.elements = l.elements[:len(l.elements)-1]
+			return last
+		}}
+	case "len":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			return int64(len(l.elements))
+		}}
+	case "insert":
+		return &nativeMethod{name: name.lexeme, n: 2, fn: func(args []value) value {
+			// inserting at len(xs) appends
+			i := l.index(name, args[0], len(l.elements)+1)
+			l.elements = append(l.elements, nil)
+			copy(l.elements[i+1:], l.elements[i:])
+			l.elements[i] = args[1]
+			return nil
+		}}
+	case "remove":
+		return &nativeMethod{name: name.lexeme, n: 1, fn: func(args []value) value {
+			i := l.index(name, args[0], len(l.elements))
+			removed := l.elements[i]
+			l.elements = append(l.elements[:i], l.elements[i+1:]...)
+			return removed
+		}}
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
+
+// ------------------------------------------
+// nativeMethod is a built-in method already bound to its receiver
+
+type nativeMethod struct {
+	name string
+	n    int
+	fn   func(args []value) value
+}
+
+func (m *nativeMethod) arity() int {
+	return m.n
+}
+
+func (m *nativeMethod) call(_ *Env, args []value) value {
+	return m.fn(args)
+}
+
+func (m *nativeMethod) String() string {
+	return stringify(m)
+}
@@ -0,0 +1,109 @@
+package lox
+
+import (
+	"fmt"
+)
+
+// ------------------------------------------
+// Map , the hash map value produced by {"k": 1}
+//
+// keys follow the same equality as BinaryExpr.equal , numbers, strings,
+// booleans and nil compare by value , instances by identity.
+// iteration follows insertion order
+
+type LoxMap struct {
+	entries map[value]value
+	order   []value // keys in insertion order
+}
+
+func NewLoxMap() *LoxMap {
+	return &LoxMap{entries: make(map[value]value), order: make([]value, 0)}
+}
+
+func (m *LoxMap) String() string {
+	return stringify(m)
+}
+
+// key checks k is hashable , t is used for error display. floats that hold
+// an integer become ints , so that m[1] and m[1.0] are the same entry
+func (m *LoxMap) key(t *tokenObj, k value) value {
+	switch v := k.(type) {
+	case float64:
+		if i, ok := toInt(v); ok {
+			return i
+		}
+		if v == v { // NaN never equals itself
+			return k
+		}
+	case nil, int64, string, bool, *LoxInstance, *vmInstance:
+		return k
+	}
+	runtimeErr(t, fmt.Sprintf("unhashable map key '%v'", stringify(k)))
+	return nil
+}
+
+func (m *LoxMap) getAt(t *tokenObj, k value) value {
+	v, ok := m.entries[m.key(t, k)]
+	if !ok {
+		runtimeErr(t, fmt.Sprintf("key '%v' not found in map", stringify(k)))
+	}
+	return v
+}
+
+func (m *LoxMap) setAt(t *tokenObj, k value, v value) {
+	k = m.key(t, k)
+	if _, ok := m.entries[k]; !ok {
+		m.order = append(m.order, k)
+	}
+	m.entries[k] = v
+}
+
+func (m *LoxMap) delete(t *tokenObj, k value) bool {
+	k = m.key(t, k)
+	if _, ok := m.entries[k]; !ok {
+		return false
+	}
+	delete(m.entries, k)
+	for i, o := range m.order {
+		if o == k {
+			m.order = append(m.order[:i], m.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// get returns the built-in method bound to this map , m.keys
+func (m *LoxMap) get(name *tokenObj) value {
+	switch name.lexeme {
+	case "has":
+		return &nativeMethod{name: name.lexeme, n: 1, fn: func(args []value) value {
+			_, ok := m.entries[m.key(name, args[0])]
+			return ok
+		}}
+	case "keys":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			keys := make([]value, len(m.order))
+			copy(keys, m.order)
+			return &LoxList{elements: keys}
+		}}
+	case "values":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			values := make([]value, 0, len(m.order))
+			for _, k := range m.order {
+				values = append(values, m.entries[k])
+			}
+			return &LoxList{elements: values}
+		}}
+	case "delete":
+		return &nativeMethod{name: name.lexeme, n: 1, fn: func(args []value) value {
+			return m.delete(name, args[0])
+		}}
+	case "len":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			return int64(len(m.order))
+		}}
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
@@ -0,0 +1,295 @@
+package lox
+
+import "fmt"
+
+// --------------------------------------------------------
+// Statements
+
+func (s *ExprStmt) execute(env *Env) Completion {
+	s.expression.eval(env)
+	return Completion{}
+}
+
+func (s *FunStmt) execute(env *Env) Completion {
+	fn := &FunObj{decl: s, closure: env, isInitializer: false}
+	env.defineInit(s.name.lexeme, fn) // add fun decl as env variable
+	return Completion{}
+}
+
+func (s *PrintStmt) execute(env *Env) Completion {
+	v := s.expression.eval(env)
+	fmt.Fprintln(env.globals.interp.stdout, stringify(v))
+	return Completion{}
+}
+
+func (s *VarStmt) execute(env *Env) Completion {
+	// make distinction between uninitialized value and nil-value
+	if s.init != nil {
+		v := s.init.eval(env)
+		env.defineInit(s.name.lexeme, v)
+	} else {
+		env.define(s.name.lexeme)
+	}
+	return Completion{}
+}
+
+func (s *BlockStmt) execute(env *Env) Completion {
+	return execBlock(s.list, NewEnv(env))
+}
+
+// execBlock stops at the first statement that does not complete normally
+// and hands its completion to the caller
+func execBlock(list []Stmt, env *Env) Completion {
+	for _, s := range list {
+		if c := s.execute(env); c.kind != CP_NORMAL {
+			return c
+		}
+	}
+	return Completion{}
+}
+
+func (s *ClassStmt) execute(env *Env) Completion {
+	var superClass *LoxClass
+	if s.superClass != nil {
+		sc, ok := s.superClass.eval(env).(*LoxClass)
+		if !ok {
+			runtimeErr(s.superClass.name, "Superclass must be a class.")
+		}
+		superClass = sc
+	}
+
+	env.define(s.name.lexeme)
+
+	// methods of a subclass close over an env that binds "super"
+	closure := env
+	if superClass != nil {
+		closure = NewEnv(env)
+		closure.defineInit("super", superClass)
+	}
+
+	methods := make(map[string]*FunObj)
+	for _, method := range s.methods {
+		function := &FunObj{method, closure, method.name.lexeme == "init"}
+		methods[method.name.lexeme] = function
+	}
+
+	klass := &LoxClass{name: s.name.lexeme, superClass: superClass, methods: methods}
+	env.assign(s.name, klass)
+	return Completion{}
+}
+
+type LoxClass struct {
+	name       string
+	superClass *LoxClass
+	methods    map[string]*FunObj
+}
+
+func (l *LoxClass) String() string {
+	return stringify(l)
+}
+
+// findMethod looks up the class itself first , then walks up the superclass chain
+func (l *LoxClass) findMethod(name string) *FunObj {
+	if method, ok := l.methods[name]; ok {
+		return method
+	}
+	if l.superClass != nil {
+		return l.superClass.findMethod(name)
+	}
+	return nil
+}
+
+func (l *LoxClass) call(e *Env, arg []value) value {
+	instance := &LoxInstance{
+		klass:  l,
+		fields: make(map[string]value),
+	}
+	initializer := l.findMethod("init")
+	if initializer != nil {
+		initializer.bind(instance).call(e, arg)
+	}
+	return instance
+}
+
+func (l *LoxClass) arity() int {
+	/*
+		LoxFunction initializer = findMethod("init");
+		    if (initializer == null) return 0;
+		    return initializer.arity();
+	*/
+	initializer := l.findMethod("init")
+	if initializer == nil {
+		return 0
+	}
+	return initializer.arity()
+}
+
+type LoxInstance struct {
+	klass  *LoxClass
+	fields map[string]value
+}
+
+func (l *LoxInstance) get(name *tokenObj) value {
+	v, ok := l.fields[name.lexeme]
+	if ok {
+		return v
+	}
+
+	method := l.klass.findMethod(name.lexeme)
+	if method != nil {
+		return method.bind(l)
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
+
+func (l *LoxInstance) set(name *tokenObj, v value) {
+	l.fields[name.lexeme] = v
+}
+
+func (l *LoxInstance) String() string {
+	return stringify(l)
+}
+
+func (s *IfStmt) execute(env *Env) Completion {
+	if isTruthy(s.condition.eval(env)) {
+		return s.block1.execute(env)
+	} else if s.block2 != nil {
+		return s.block2.execute(env)
+	}
+	return Completion{}
+}
+
+func (s *ForInStmt) execute(env *Env) Completion {
+	lim := env.globals.interp.limits
+	next := newIterator(s.keyword, treeIter{env}, s.iterable.eval(env), s.key != nil)
+	for {
+		k, v, ok := next()
+		if !ok {
+			break
+		}
+		if msg := lim.step(); msg != "" {
+			limitErr(s.keyword, msg)
+		}
+		loopEnv := NewEnv(env)
+		if s.key != nil {
+			loopEnv.defineInit(s.key.lexeme, k)
+		}
+		loopEnv.defineInit(s.name.lexeme, v)
+		c := s.body.execute(loopEnv)
+		if c.kind == CP_BREAK {
+			break
+		}
+		if c.kind == CP_RETURN {
+			return c
+		}
+	}
+	return Completion{}
+}
+
+func (s *ReturnStmt) execute(env *Env) Completion {
+	var v value
+	if s.value != nil {
+		v = s.value.eval(env)
+	}
+	return Completion{kind: CP_RETURN, value: v}
+}
+
+func (s *BreakStmt) execute(env *Env) Completion {
+	return Completion{kind: CP_BREAK}
+}
+
+func (s *ContinueStmt) execute(env *Env) Completion {
+	return Completion{kind: CP_CONTINUE}
+}
+
+func (s *ImportStmt) execute(env *Env) Completion {
+	m := env.globals.interp.importModule(s.keyword, s.path.literal.(string))
+	if s.name != nil {
+		env.defineInit(s.name.lexeme, m)
+	}
+	for i, name := range s.exports {
+		env.defineInit(s.aliases[i].lexeme, m.get(name))
+	}
+	return Completion{}
+}
+
+// yield hands the value to the resumer and waits for the next resume
+func (s *YieldStmt) execute(env *Env) Completion {
+	var v value
+	if s.value != nil {
+		v = s.value.eval(env)
+	}
+	co := env.coroutine()
+	co.yield <- genResult{v: v}
+	co.wait()
+	return Completion{}
+}
+
+func (s *ThrowStmt) execute(env *Env) Completion {
+	panic(throwError(s.keyword.span, s.value.eval(env)))
+}
+
+// a return , break or continue in finally wins over the pending completion
+// or error , the limits' fatal errors skip catch and finally
+func (s *TryStmt) execute(env *Env) Completion {
+	lim := env.globals.interp.limits
+	depth := lim.depth
+	c, err := protect(lim, depth, func() Completion {
+		return execBlock(s.body, NewEnv(env))
+	})
+	if err != nil && s.name != nil {
+		v, _ := catchValue(*err)
+		catchEnv := NewEnv(env)
+		catchEnv.defineInit(s.name.lexeme, v)
+		c, err = protect(lim, depth, func() Completion {
+			return execBlock(s.catch, catchEnv)
+		})
+	}
+	if s.finally != nil {
+		if fc := execBlock(s.finally, NewEnv(env)); fc.kind != CP_NORMAL {
+			return fc
+		}
+	}
+	if err != nil {
+		panic(*err)
+	}
+	return c
+}
+
+// protect runs f and recovers the errors try can catch , the call depth is
+// reset to where the try started
+func protect(lim *limits, depth int, f func() Completion) (c Completion, err *RuntimeError) {
+	defer func() {
+		if e := recover(); e != nil {
+			re, ok := e.(RuntimeError)
+			if !ok || re.fatal {
+				panic(e)
+			}
+			lim.depth = depth
+			err = &re
+		}
+	}()
+	return f(), nil
+}
+
+func (s *WhileStmt) execute(env *Env) Completion {
+	lim := env.globals.interp.limits
+	for isTruthy(s.condition.eval(env)) {
+		if msg := lim.step(); msg != "" {
+			limitErr(s.keyword, msg)
+		}
+		c := s.body.execute(env)
+		if c.kind == CP_BREAK {
+			break
+		}
+		if c.kind == CP_RETURN {
+			return c
+		}
+		// normal or continue , both go on with the next iteration
+		if s.increment != nil {
+			s.increment.eval(env)
+		}
+	}
+	return Completion{}
+}
@@ -0,0 +1,25 @@
+package lox
+
+import "unicode/utf8"
+
+// ------------------------------------------
+// String methods and indexing , strings are plain Go strings holding UTF-8 ,
+// lengths and positions count runes (code points) , not bytes
+
+// stringGet returns the built-in method bound to s , s.len
+func stringGet(s string, name *tokenObj) value {
+	switch name.lexeme {
+	case "len":
+		return &nativeMethod{name: name.lexeme, n: 0, fn: func(args []value) value {
+			return int64(utf8.RuneCountInString(s))
+		}}
+	}
+	runtimeErr(name, "Undefined property '"+name.lexeme+"'.")
+	return nil
+}
+
+// stringAt is s[i] , the rune at i as a string of its own
+func stringAt(t *tokenObj, s string, i value) value {
+	runes := []rune(s)
+	return string(runes[checkIndex(t, "string", i, len(runes), len(runes))])
+}
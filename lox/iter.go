@@ -0,0 +1,170 @@
+package lox
+
+import (
+	"fmt"
+)
+
+// ------------------------------------------
+// for (x in xs) , iteration shared by both backends
+//
+// lists , strings , maps , ranges and generators are stepped through
+// natively , with two variables a list or string gives index and element and
+// a map key and value.
+// any other value needs an iter() method returning an iterator , whose next()
+// returns the next element or nil once it is done
+
+// iterator returns the next key and element , ok is false at the end
+type iterator func() (key, v value, ok bool)
+
+// iterHost calls Lox methods for an iterator , each backend has its own
+type iterHost interface {
+	method(v value, name string) (value, bool)
+	invoke(t *tokenObj, fn value) value
+}
+
+// newIterator starts iterating v , t is the for keyword
+func newIterator(t *tokenObj, h iterHost, v value, pair bool) iterator {
+	switch o := v.(type) {
+	case *LoxList:
+		i := 0
+		return func() (value, value, bool) {
+			if i >= len(o.elements) {
+				return nil, nil, false
+			}
+			i++
+			return int64(i - 1), o.elements[i-1], true
+		}
+	case string:
+		runes := []rune(o)
+		i := 0
+		return func() (value, value, bool) {
+			if i >= len(runes) {
+				return nil, nil, false
+			}
+			i++
+			return int64(i - 1), string(runes[i-1]), true
+		}
+	case *LoxMap:
+		keys := append([]value(nil), o.order...) // the keys when the loop started
+		i := 0
+		return func() (value, value, bool) {
+			for i < len(keys) {
+				k := keys[i]
+				i++
+				if e, ok := o.entries[k]; ok {
+					if pair {
+						return k, e, true
+					}
+					return nil, k, true
+				}
+			}
+			return nil, nil, false
+		}
+	}
+
+	if pair {
+		runtimeErr(t, fmt.Sprintf("can't iterate over %v with two variables", typeName(v)))
+	}
+	if r, ok := v.(*LoxRange); ok {
+		return r.iterator()
+	}
+	if g, ok := v.(*LoxGenerator); ok {
+		return func() (value, value, bool) {
+			e, ok := g.next(t)
+			return nil, e, ok
+		}
+	}
+	iter, ok := h.method(v, "iter")
+	if !ok {
+		runtimeErr(t, fmt.Sprintf("%v is not iterable", typeName(v)))
+	}
+	it := h.invoke(t, iter)
+	next, ok := h.method(it, "next")
+	if !ok {
+		runtimeErr(t, fmt.Sprintf("iter() returned %v without a next() method", typeName(it)))
+	}
+	return func() (value, value, bool) {
+		e := h.invoke(t, next)
+		return nil, e, e != nil
+	}
+}
+
+// treeIter is the tree-walker's iterHost
+type treeIter struct {
+	env *Env
+}
+
+func (h treeIter) method(v value, name string) (value, bool) {
+	o, ok := v.(*LoxInstance)
+	if !ok {
+		return nil, false
+	}
+	if f, ok := o.fields[name]; ok {
+		return f, true
+	}
+	if m := o.klass.findMethod(name); m != nil {
+		return m.bind(o), true
+	}
+	return nil, false
+}
+
+func (h treeIter) invoke(t *tokenObj, fn value) value {
+	c, ok := fn.(Callable)
+	if !ok {
+		runtimeErr(t, fmt.Sprintf("'%v' is not a function or class", stringify(fn)))
+	}
+	if c.arity() > 0 {
+		runtimeErr(t, fmt.Sprintf("expected %v arguments but got 0", c.arity()))
+	}
+	lim := h.env.globals.interp.limits
+	lim.enter(t)
+	v := c.call(h.env, nil)
+	lim.leave()
+	return v
+}
+
+// ------------------------------------------
+// LoxRange , range(end) range(start, end) range(start, end, step)
+
+type LoxRange struct {
+	start, end, step int64
+}
+
+func newRange(args ...int64) (*LoxRange, error) {
+	r := &LoxRange{step: 1}
+	switch len(args) {
+	case 1:
+		r.end = args[0]
+	case 2:
+		r.start, r.end = args[0], args[1]
+	case 3:
+		r.start, r.end, r.step = args[0], args[1], args[2]
+	default:
+		return nil, fmt.Errorf("expected 1 to 3 arguments but got %v", len(args))
+	}
+	if r.step == 0 {
+		return nil, fmt.Errorf("range step must not be zero")
+	}
+	return r, nil
+}
+
+func (r *LoxRange) String() string {
+	return stringify(r)
+}
+
+func (r *LoxRange) iterator() iterator {
+	i, done := r.start, false
+	return func() (value, value, bool) {
+		if done || (r.step > 0 && i >= r.end) || (r.step < 0 && i <= r.end) {
+			return nil, nil, false
+		}
+		v := i
+		next, msg := intArith(Plus, i, r.step)
+		if msg != "" {
+			done = true // the next step is past int64 , so past end too
+		} else {
+			i = next.(int64)
+		}
+		return nil, v, true
+	}
+}
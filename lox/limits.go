@@ -0,0 +1,90 @@
+package lox
+
+import (
+	"context"
+)
+
+// ------------------------------------------
+// resource limits , so that a host can run untrusted scripts
+//
+// both backends count a step for every loop iteration and every call of a Lox
+// function , which are the only ways a script can run for long. the context
+// is polled every checkEvery steps
+
+const (
+	defaultMaxDepth = 1024
+	checkEvery      = 1024
+)
+
+type limits struct {
+	maxSteps int64 // 0 means no budget
+	maxDepth int
+
+	steps   int64
+	depth   int // tree-walker only , the VM counts its frames
+	ctx     context.Context
+	running int // Evals and Calls in progress , nested ones come from Go functions
+}
+
+func newLimits(opts Options) *limits {
+	l := &limits{maxSteps: opts.MaxSteps, maxDepth: opts.MaxDepth, ctx: context.Background()}
+	if l.maxDepth <= 0 {
+		l.maxDepth = defaultMaxDepth
+	}
+	return l
+}
+
+// begin starts an Eval or Call with a fresh budget and ctx , unless it is
+// nested in one already running , like a Go function calling back into Lox ,
+// which keeps counting against the outer budget and ctx. the returned end
+// puts the depth back , also after an error
+func (l *limits) begin(ctx context.Context) (end func()) {
+	if l.running == 0 {
+		l.steps = 0
+		l.depth = 0
+		l.ctx = ctx
+	}
+	l.running++
+	depth := l.depth
+	return func() {
+		l.depth = depth
+		l.running--
+	}
+}
+
+// step returns the reason the run has to stop , or "" to go on
+func (l *limits) step() string {
+	l.steps++
+	if l.maxSteps > 0 && l.steps > l.maxSteps {
+		return "step budget exceeded"
+	}
+	if l.steps%checkEvery == 0 {
+		if err := l.ctx.Err(); err != nil {
+			return "execution cancelled: " + err.Error()
+		}
+	}
+	return ""
+}
+
+// limitErr raises msg at t , it is fatal so that try can't catch it and run
+// on past the budget
+func limitErr(t *tokenObj, msg string) {
+	err := newRuntimeError(t.span, msg)
+	err.fatal = true
+	panic(err)
+}
+
+// enter is step for a tree-walker call , t is the call site
+func (l *limits) enter(t *tokenObj) {
+	if msg := l.step(); msg != "" {
+		limitErr(t, msg)
+	}
+	if l.depth == l.maxDepth {
+		runtimeErr(t, "stack overflow")
+	}
+	l.depth++
+}
+
+func (l *limits) leave() {
+	l.depth--
+}
@@ -0,0 +1,241 @@
+// Package lox is an embeddable Lox interpreter.
+//
+// An Interpreter keeps its global variables between calls , so a host can
+// Define values , Eval scripts and Call back into the functions they declare.
+// Interpreters share no state , each one may run on its own goroutine , but a
+// single Interpreter must not be used by two goroutines at once.
+//
+//	in := lox.NewInterpreter(lox.Options{Stdout: &buf})
+//	in.Define("limit", 10.0)
+//	v, err := in.Eval(`fun twice(n) { return n * 2; } twice(limit);`)
+package lox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// Value is any Lox value , nil , bool , int64 , float64 , string or one of the
+// interpreter's own objects (functions , classes , instances , lists , maps)
+type Value = value
+
+// Options configures a new Interpreter , the zero value is ready to use
+type Options struct {
+	Stdout io.Writer // print statements , defaults to os.Stdout
+	Stderr io.Writer // errors reported by Run , defaults to os.Stderr
+
+	VM     bool // run on the bytecode VM instead of the tree-walker
+	Disasm bool // write the compiled bytecode to Stderr before running , needs VM
+
+	// limits for untrusted scripts , a step is a loop iteration or a call
+	MaxSteps int64 // steps per Eval or Call , 0 means no limit
+	MaxDepth int   // nested calls before a "stack overflow" error , defaults to 1024
+
+	// directories searched for an import after the importing file's own
+	ImportPath []string
+}
+
+type Interpreter struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	globals *Env // tree-walker globals
+	vm      *VM  // nil unless Options.VM
+	running *VM  // in.vm or the VM of the generator being resumed
+	disasm  bool
+	limits  *limits
+
+	builtins   map[string]value // everything Defined , copied into every module
+	importPath []string
+	modules    map[string]*LoxModule // by absolute path
+	loading    []loadingModule
+	sources    map[string]string // by file name , for FormatError
+}
+
+func NewInterpreter(opts Options) *Interpreter {
+	in := &Interpreter{
+		stdout: opts.Stdout,
+		stderr: opts.Stderr,
+		disasm: opts.Disasm,
+		limits: newLimits(opts),
+
+		builtins:   make(map[string]value),
+		importPath: opts.ImportPath,
+		modules:    make(map[string]*LoxModule),
+		sources:    make(map[string]string),
+	}
+	if in.stdout == nil {
+		in.stdout = os.Stdout
+	}
+	if in.stderr == nil {
+		in.stderr = os.Stderr
+	}
+
+	if opts.VM {
+		in.vm = NewVM(in.stdout)
+		in.vm.limits = in.limits
+		in.vm.interp = in
+		in.running = in.vm
+	} else {
+		in.globals = in.newGlobals()
+	}
+	in.Define("clock", clockFn{})
+	in.Define("str", strFn{})
+	rangeFn, _ := newGoFunc("range", newRange)
+	in.Define("range", rangeFn)
+	return in
+}
+
+// Eval runs source in the interpreter's global scope. If the last statement
+// is an expression statement its value is returned.
+func (in *Interpreter) Eval(source string) (Value, error) {
+	return in.EvalContext(context.Background(), source)
+}
+
+// EvalContext is Eval , stopping with a runtime error once ctx is done
+func (in *Interpreter) EvalContext(ctx context.Context, source string) (Value, error) {
+	return in.eval(ctx, "", source)
+}
+
+// EvalFile is Eval for the contents of file , the name shows up in the
+// position of errors
+func (in *Interpreter) EvalFile(file, source string) (Value, error) {
+	return in.eval(context.Background(), file, source)
+}
+
+func (in *Interpreter) eval(ctx context.Context, file, source string) (Value, error) {
+	stmts, err := in.parse(file, source)
+	if err != nil {
+		return nil, err
+	}
+
+	defer in.limits.begin(ctx)()
+	if file != "" {
+		// so that a module importing this file again is a cycle
+		if abs, err := filepath.Abs(file); err == nil {
+			in.loading = append(in.loading, loadingModule{abs: abs, file: file})
+			defer func() { in.loading = in.loading[:0] }()
+		}
+	}
+
+	if in.vm != nil {
+		script, errs := compile(stmts)
+		if len(errs) > 0 {
+			return nil, errors.Join(errs...)
+		}
+		if in.disasm {
+			fmt.Fprint(in.stderr, disassemble(script))
+		}
+		return in.vm.interpret(script)
+	}
+	return interpret(stmts, in.globals)
+}
+
+// parse scans , parses and resolves source
+func (in *Interpreter) parse(file, source string) ([]Stmt, error) {
+	in.sources[file] = source
+	scanner := NewScanner(file, source)
+	tokens, scanErrs := scanner.scan()
+
+	p := NewParser(tokens)
+	stmts, errs := p.parse()
+	if errs = append(scanErrs, errs...); len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			return errs[i].(*Diagnostic).Span.Start < errs[j].(*Diagnostic).Span.Start
+		})
+		return nil, errors.Join(errs...)
+	}
+
+	resolver := NewResolver()
+	resolver.resolve(stmts)
+	if len(resolver.errs) > 0 {
+		return nil, errors.Join(resolver.errs...)
+	}
+	return stmts, nil
+}
+
+// Run is Eval for command line use , every error is written to Stderr
+// with the source line it points at. It reports whether source ran without
+// errors.
+func (in *Interpreter) Run(source string) bool {
+	return in.RunFile("", source)
+}
+
+// RunFile is Run for the contents of file
+func (in *Interpreter) RunFile(file, source string) bool {
+	if _, err := in.EvalFile(file, source); err != nil {
+		fmt.Fprintln(in.stderr, in.FormatError(err))
+		return false
+	}
+	return true
+}
+
+// FormatError renders the message of err with the source span it points at
+// underlined , taken from whichever file this interpreter ran it in
+func (in *Interpreter) FormatError(err error) string {
+	return formatError(err, func(d *Diagnostic) string {
+		return in.sources[d.Span.File]
+	})
+}
+
+// Stringify formats v the way print does
+func Stringify(v Value) string {
+	return stringify(v)
+}
+
+// Define binds a global variable , visible to every later Eval and to the
+// modules imported after it. Go numbers become Lox ints or floats like the
+// results of RegisterFunc
+func (in *Interpreter) Define(name string, v Value) {
+	v = fromGo(reflect.ValueOf(v))
+	in.builtins[name] = v
+	if in.vm != nil {
+		in.vm.globals[name] = v
+		return
+	}
+	in.globals.defineInit(name, v)
+}
+
+// newGlobals is a tree-walker global scope holding the builtins
+func (in *Interpreter) newGlobals() *Env {
+	env := NewEnv(nil) // root env has no enclosure
+	env.interp = in
+	for name, v := range in.builtins {
+		env.defineInit(name, v)
+	}
+	return env
+}
+
+// vmGlobals is newGlobals for the VM
+func (in *Interpreter) vmGlobals() map[string]value {
+	globals := make(map[string]value, len(in.builtins))
+	for name, v := range in.builtins {
+		globals[name] = v
+	}
+	return globals
+}
+
+// Call calls a Lox function , class or bound method , usually one taken out
+// of the result of Eval
+func (in *Interpreter) Call(fn Value, args ...Value) (Value, error) {
+	return in.CallContext(context.Background(), fn, args...)
+}
+
+// CallContext is Call , stopping with a runtime error once ctx is done
+func (in *Interpreter) CallContext(ctx context.Context, fn Value, args ...Value) (Value, error) {
+	defer in.limits.begin(ctx)()
+	converted := make([]value, len(args)) // not in place , args may be the caller's slice
+	for i, a := range args {
+		converted[i] = fromGo(reflect.ValueOf(a))
+	}
+	if in.vm != nil {
+		return in.vm.callFunction(fn, converted)
+	}
+	return callFunction(in.globals, fn, converted)
+}
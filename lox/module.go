@@ -0,0 +1,146 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ------------------------------------------
+// modules , import "lib.glx" as lib;
+//
+// a path is looked up next to the importing file , then in Options.ImportPath.
+// every file runs once per Interpreter in its own globals , the builtins and
+// whatever the host Defined are copied in. the module's own top-level var ,
+// fun and class declarations are its exports
+
+type LoxModule struct {
+	name    string // the path as written in the import
+	exports map[string]value
+}
+
+func (m *LoxModule) String() string {
+	return stringify(m)
+}
+
+func (m *LoxModule) get(name *tokenObj) value {
+	v, ok := m.exports[name.lexeme]
+	if !ok {
+		runtimeErr(name, fmt.Sprintf("module '%v' has no export '%v'", m.name, name.lexeme))
+	}
+	return v
+}
+
+// loadingModule is a file whose top level is running , for cycle errors
+type loadingModule struct {
+	abs  string
+	file string
+}
+
+// importModule runs the module path , or returns it from the cache , t is the
+// import keyword
+func (in *Interpreter) importModule(t *tokenObj, path string) *LoxModule {
+	file := in.findModule(t, path)
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		runtimeErr(t, err.Error())
+	}
+	if m, ok := in.modules[abs]; ok {
+		return m
+	}
+	for i, l := range in.loading {
+		if l.abs == abs {
+			chain := []string{}
+			for _, l := range in.loading[i:] {
+				chain = append(chain, l.file)
+			}
+			runtimeErr(t, "import cycle: "+strings.Join(append(chain, file), " -> "))
+		}
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		runtimeErr(t, fmt.Sprintf("cannot import '%v': %v", path, err))
+	}
+	stmts, err := in.parse(file, string(data))
+	if err != nil {
+		importError(t, path, err)
+	}
+
+	in.loading = append(in.loading, loadingModule{abs: abs, file: file})
+	defer func() { in.loading = in.loading[:len(in.loading)-1] }()
+	m := &LoxModule{name: path, exports: make(map[string]value)}
+	if in.vm != nil {
+		script, errs := compile(stmts)
+		if len(errs) > 0 {
+			importError(t, path, errors.Join(errs...))
+		}
+		if in.disasm {
+			fmt.Fprint(in.stderr, disassemble(script))
+		}
+		globals := in.vmGlobals()
+		in.vm.runModule(script, globals)
+		for _, name := range exportNames(stmts) {
+			v := globals[name]
+			if _, ok := v.(uninitialized); ok {
+				v = nil
+			}
+			m.exports[name] = v
+		}
+	} else {
+		env := in.newGlobals()
+		if _, err := interpret(stmts, env); err != nil {
+			panic(err)
+		}
+		for _, name := range exportNames(stmts) {
+			m.exports[name] = env.values[name]
+		}
+	}
+	in.modules[abs] = m
+	return m
+}
+
+// importError reports the compile errors of a module at the import , one
+// note each
+func importError(t *tokenObj, path string, err error) {
+	re := newRuntimeError(t.span, fmt.Sprintf("cannot import '%v'", path))
+	for _, d := range Diagnostics(err) {
+		re.Notes = append(re.Notes, fmt.Sprintf("%v: %v", d.Span, d.Message))
+	}
+	panic(re)
+}
+
+// findModule is the file path names , relative to the file of t first
+func (in *Interpreter) findModule(t *tokenObj, path string) string {
+	dirs := append([]string{filepath.Dir(t.span.File)}, in.importPath...)
+	if filepath.IsAbs(path) {
+		dirs = []string{""}
+	}
+	for _, dir := range dirs {
+		file := filepath.Join(dir, path)
+		if st, err := os.Stat(file); err == nil && !st.IsDir() {
+			return file
+		}
+	}
+	re := newRuntimeError(t.span, fmt.Sprintf("cannot find module '%v'", path))
+	re.Notes = []string{"searched " + strings.Join(dirs, ", ")}
+	panic(re)
+}
+
+// exportNames lists the top-level declarations of a module
+func exportNames(stmts []Stmt) []string {
+	names := []string{}
+	for _, s := range stmts {
+		switch s := s.(type) {
+		case *VarStmt:
+			names = append(names, s.name.lexeme)
+		case *FunStmt:
+			names = append(names, s.name.lexeme)
+		case *ClassStmt:
+			names = append(names, s.name.lexeme)
+		}
+	}
+	return names
+}
@@ -0,0 +1,261 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ------------------------------------------
+// native functions registered from Go , see Interpreter.RegisterFunc
+//
+// arguments are converted from Lox values to the Go parameter types and the
+// results back , a trailing error result becomes a Lox runtime error at the
+// line of the call
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+type goFunc struct {
+	name     string
+	fn       reflect.Value
+	params   []reflect.Type // variadic: the last one is the element type
+	variadic bool
+	hasErr   bool // last result is an error
+}
+
+// RegisterFunc defines fn as the global function name , for example
+//
+//	in.RegisterFunc("sqrt", math.Sqrt)
+//
+// Parameters may be numbers (any int, uint or float kind), string, bool , or
+// any type a Lox value is assignable to , such as Value. fn may be variadic
+// and may return nothing , one value , or a value and an error.
+func (in *Interpreter) RegisterFunc(name string, fn interface{}) error {
+	f, err := newGoFunc(name, fn)
+	if err != nil {
+		return err
+	}
+	in.Define(name, f)
+	return nil
+}
+
+func newGoFunc(name string, fn interface{}) (*goFunc, error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return nil, fmt.Errorf("RegisterFunc %v: expected a func, got %T", name, fn)
+	}
+	t := v.Type()
+	f := &goFunc{name: name, fn: v, variadic: t.IsVariadic()}
+	for i := 0; i < t.NumIn(); i++ {
+		p := t.In(i)
+		if f.variadic && i == t.NumIn()-1 {
+			p = p.Elem()
+		}
+		f.params = append(f.params, p)
+	}
+
+	switch {
+	case t.NumOut() == 0:
+	case t.NumOut() == 1:
+		f.hasErr = t.Out(0) == errorType
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+		f.hasErr = true
+	default:
+		return nil, fmt.Errorf("RegisterFunc %v: expected at most a result and an error, got %v", name, t)
+	}
+	return f, nil
+}
+
+// arity is -1 for variadic functions , callAt checks the argument count itself
+func (f *goFunc) arity() int {
+	if f.variadic {
+		return -1
+	}
+	return len(f.params)
+}
+
+func (f *goFunc) call(_ *Env, args []value) value {
+	return f.callAt(nil, args)
+}
+
+// callAt calls the Go function , paren is the call site for error display
+// and is nil when called from Go
+func (f *goFunc) callAt(paren *tokenObj, args []value) value {
+	fail := func(msg string) {
+		if paren == nil {
+			panic(newRuntimeError(Span{}, msg))
+		}
+		runtimeErr(paren, msg)
+	}
+
+	if f.variadic && len(args) < len(f.params)-1 {
+		fail(fmt.Sprintf("expected at least %v arguments but got %v", len(f.params)-1, len(args)))
+	} else if !f.variadic && len(args) != len(f.params) {
+		fail(fmt.Sprintf("expected %v arguments but got %v", len(f.params), len(args)))
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		p := f.params[len(f.params)-1]
+		if i < len(f.params) {
+			p = f.params[i]
+		}
+		v, err := toGo(a, p)
+		if err != nil {
+			fail(fmt.Sprintf("argument %v to '%v' %v", i+1, f.name, err))
+		}
+		in[i] = v
+	}
+
+	out := f.invoke(in, fail)
+	if f.hasErr {
+		if err := out[len(out)-1]; !err.IsNil() {
+			if re, ok := err.Interface().(RuntimeError); ok {
+				panic(re) // from a call back into Lox , keeps what try may catch
+			}
+			fail(err.Interface().(error).Error())
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return fromGo(out[0])
+}
+
+// invoke runs the Go function , a panic in it becomes a runtime error instead
+// of taking down the host
+func (f *goFunc) invoke(in []reflect.Value, fail func(string)) (out []reflect.Value) {
+	defer func() {
+		if e := recover(); e != nil {
+			if _, ok := e.(RuntimeError); ok {
+				panic(e) // a Lox error from a call back into the interpreter
+			}
+			fail(fmt.Sprintf("'%v' panicked: %v", f.name, e))
+		}
+	}()
+	return f.fn.Call(in)
+}
+
+func (f *goFunc) String() string {
+	return stringify(f)
+}
+
+// toGo converts a Lox value into a parameter of type t
+func toGo(v value, t reflect.Type) (reflect.Value, error) {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if isNumber(v) {
+			return reflect.ValueOf(toFloat(v)).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a number, got %v", typeName(v))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if !isNumber(v) {
+			return reflect.Value{}, fmt.Errorf("must be a number, got %v", typeName(v))
+		}
+		i, ok := toInt(v)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("must be an integer, got %v", stringify(v))
+		}
+		r := reflect.New(t).Elem()
+		switch t.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if i < 0 || r.OverflowUint(uint64(i)) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %v", i, t)
+			}
+			r.SetUint(uint64(i))
+		default:
+			if r.OverflowInt(i) {
+				return reflect.Value{}, fmt.Errorf("%v overflows %v", i, t)
+			}
+			r.SetInt(i)
+		}
+		return r, nil
+	case reflect.String:
+		if s, ok := v.(string); ok {
+			return reflect.ValueOf(s).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a string, got %v", typeName(v))
+	case reflect.Bool:
+		if b, ok := v.(bool); ok {
+			return reflect.ValueOf(b).Convert(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("must be a boolean, got %v", typeName(v))
+	}
+
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+	} else if rv := reflect.ValueOf(v); rv.Type().AssignableTo(t) {
+		return rv, nil
+	}
+	return reflect.Value{}, fmt.Errorf("must be %v, got %v", t, typeName(v))
+}
+
+// fromGo converts a Go result back into a Lox value
+func fromGo(v reflect.Value) value {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := v.Uint(); u <= math.MaxInt64 {
+			return int64(u)
+		}
+		return float64(v.Uint()) // too large for an int
+
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return fromGo(v.Elem())
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	return v.Interface()
+}
+
+// typeName names the Lox type of v for error messages
+func typeName(v value) string {
+	switch v.(type) {
+	case nil:
+		return "nil"
+	case int64, float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case *LoxInstance, *vmInstance:
+		return "instance"
+	case *LoxClass, *vmClass:
+		return "class"
+	case *LoxList:
+		return "list"
+	case *LoxMap:
+		return "map"
+	case *LoxError:
+		return "error"
+	case *LoxModule:
+		return "module"
+	case *LoxRange:
+		return "range"
+	case *LoxGenerator:
+		return "generator"
+	case Callable, *vmClosure, *vmBoundMethod:
+		return "function"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,263 @@
+package lox
+
+import (
+	"math"
+)
+
+// ------------------------------------------
+// numbers , integer literals are int64 and the rest float64
+//
+// int op int stays an int and overflow is a runtime error , an int mixed with
+// a float becomes a float. / always divides exactly , div floors and % takes
+// the sign of the right operand , so that x == (x div y) * y + x % y. div is
+// a keyword because // starts a comment.
+// ** with a negative int exponent gives a float. & | ^ ~ << >> take ints only
+
+const errIntOverflow = "integer overflow"
+
+func isNumber(v value) bool {
+	switch v.(type) {
+	case int64, float64:
+		return true
+	}
+	return false
+}
+
+func toFloat(v value) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+// toInt is v as an int64 , for floats only when they hold an integer that
+// int64 can represent
+func toInt(v value) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		if n == math.Trunc(n) && n >= -(1<<63) && n < 1<<63 {
+			return int64(n), true
+		}
+	}
+	return 0, false
+}
+
+// arith applies a numeric binary operator for both backends , the string is
+// the error message when it fails
+func arith(op token, x, y value) (value, string) {
+	if !isNumber(x) {
+		return nil, "left operand must be a number"
+	}
+	if !isNumber(y) {
+		return nil, "right operand must be a number"
+	}
+	switch op {
+	case Amp, Pipe, Caret, LessLess, GreaterGreater:
+		return bitwise(op, x, y)
+	}
+	a, aok := x.(int64)
+	b, bok := y.(int64)
+	if aok && bok {
+		return intArith(op, a, b)
+	}
+	f, g := toFloat(x), toFloat(y)
+	switch op {
+	case Plus:
+		return f + g, ""
+	case Minus:
+		return f - g, ""
+	case Star:
+		return f * g, ""
+	case StarStar:
+		return math.Pow(f, g), ""
+	case Slash:
+		if g == 0 {
+			return nil, "division by zero"
+		}
+		return f / g, ""
+	case Div:
+		if g == 0 {
+			return nil, "division by zero"
+		}
+		return math.Floor(f / g), ""
+	case Percent:
+		if g == 0 {
+			return nil, "modulo by zero"
+		}
+		m := math.Mod(f, g)
+		if m != 0 && (m < 0) != (g < 0) {
+			m += g
+		}
+		return m, ""
+	case Greater:
+		return f > g, ""
+	case GreaterEqual:
+		return f >= g, ""
+	case Less:
+		return f < g, ""
+	}
+	return f <= g, "" // LessEqual
+}
+
+func intArith(op token, a, b int64) (value, string) {
+	switch op {
+	case Plus:
+		r := a + b
+		if (a >= 0) == (b >= 0) && (r >= 0) != (a >= 0) {
+			return nil, errIntOverflow
+		}
+		return r, ""
+	case Minus:
+		r := a - b
+		if (a >= 0) != (b >= 0) && (r >= 0) != (a >= 0) {
+			return nil, errIntOverflow
+		}
+		return r, ""
+	case Star:
+		if a == 0 || b == 0 {
+			return int64(0), ""
+		}
+		r := a * b
+		if r/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+			return nil, errIntOverflow
+		}
+		return r, ""
+	case StarStar:
+		if b < 0 {
+			return math.Pow(float64(a), float64(b)), ""
+		}
+		return intPow(a, b)
+	case Slash:
+		if b == 0 {
+			return nil, "division by zero"
+		}
+		return float64(a) / float64(b), ""
+	case Div:
+		if b == 0 {
+			return nil, "division by zero"
+		}
+		if a == math.MinInt64 && b == -1 {
+			return nil, errIntOverflow
+		}
+		q := a / b
+		if a%b != 0 && (a < 0) != (b < 0) {
+			q--
+		}
+		return q, ""
+	case Percent:
+		if b == 0 {
+			return nil, "modulo by zero"
+		}
+		if b == -1 {
+			return int64(0), "" // MinInt64 % -1 traps
+		}
+		m := a % b
+		if m != 0 && (m < 0) != (b < 0) {
+			m += b
+		}
+		return m, ""
+	case Greater:
+		return a > b, ""
+	case GreaterEqual:
+		return a >= b, ""
+	case Less:
+		return a < b, ""
+	}
+	return a <= b, "" // LessEqual
+}
+
+// intPow is a ** b for b >= 0 , by repeated squaring
+func intPow(a, b int64) (value, string) {
+	r := int64(1)
+	for b > 0 {
+		if b&1 == 1 {
+			v, msg := intArith(Star, r, a)
+			if msg != "" {
+				return nil, msg
+			}
+			r = v.(int64)
+		}
+		b >>= 1
+		if b > 0 {
+			v, msg := intArith(Star, a, a)
+			if msg != "" {
+				return nil, msg
+			}
+			a = v.(int64)
+		}
+	}
+	return r, ""
+}
+
+func bitwise(op token, x, y value) (value, string) {
+	a, ok := x.(int64)
+	if !ok {
+		return nil, "left operand must be an integer"
+	}
+	b, ok := y.(int64)
+	if !ok {
+		return nil, "right operand must be an integer"
+	}
+	switch op {
+	case Amp:
+		return a & b, ""
+	case Pipe:
+		return a | b, ""
+	case Caret:
+		return a ^ b, ""
+	}
+	if b < 0 {
+		return nil, "negative shift count"
+	}
+	if op == GreaterGreater {
+		return a >> b, ""
+	}
+	r := a << b
+	if r>>b != a {
+		return nil, errIntOverflow
+	}
+	return r, ""
+}
+
+// negate is unary minus
+func negate(v value) (value, string) {
+	switch n := v.(type) {
+	case int64:
+		if n == math.MinInt64 {
+			return nil, errIntOverflow
+		}
+		return -n, ""
+	case float64:
+		return -n, ""
+	}
+	return nil, "operand must be a number"
+}
+
+// complement is unary ~
+func complement(v value) (value, string) {
+	if n, ok := v.(int64); ok {
+		return ^n, ""
+	}
+	return nil, "operand must be an integer"
+}
+
+// valuesEqual is == for both backends , 1 == 1.0
+func valuesEqual(x, y value) bool {
+	if isNumber(x) && isNumber(y) {
+		a, aok := x.(int64)
+		b, bok := y.(int64)
+		if aok && bok {
+			return a == b
+		}
+		if aok || bok {
+			// compare exactly , float64(a) would round ints beyond 2^53
+			i, ok := toInt(x)
+			j, ok2 := toInt(y)
+			return ok && ok2 && i == j
+		}
+		return toFloat(x) == toFloat(y)
+	}
+	return x == y
+}
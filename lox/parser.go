@@ -1,6 +1,4 @@
-package main
-
-import "fmt"
+package lox
 
 // Recursive-descent parser
 //
@@ -8,14 +6,20 @@ import "fmt"
 //
 // declaration    -> classDecl
 //				   | funDecl
+//                 | importDecl
 //                 | lambdaCall
 //                 | varDecl
 //                 | statement ;
 //
+// classDecl      -> "class" IDENTIFIER ( "<" IDENTIFIER )? "{" function* "}" ;
 // funDecl        -> "fun" function ;
 // function       -> IDENTIFIER "(" parameters? ")" block ;
 // parameters     -> IDENTIFIER ( "," IDENTIFIER )* ;
 //
+// importDecl     -> "import" STRING "as" IDENTIFIER ";"
+//                 | "from" STRING "import" importName ( "," importName )* ";" ;
+// importName     -> IDENTIFIER ( "as" IDENTIFIER )? ;
+//
 // lambdaCall     -> funExpr "(" arguments? ")" ";" ;
 //
 // varDecl        -> "var" IDENTIFIER ( "=" expression )? ";" ;
@@ -27,7 +31,10 @@ import "fmt"
 //                 | ifStmt
 //                 | printStmt
 //                 | returnStmt
+//                 | throwStmt
+//                 | tryStmt
 //                 | whileStmt
+//                 | yieldStmt
 //				   | block ;
 //
 // block		  -> "{" declaration* "}" ;
@@ -36,40 +43,57 @@ import "fmt"
 // exprStmt       -> expression ";" ;
 // forStmt        -> "for" "(" ( varDecl | exprStmt | ";" )
 //                   expression? ";"
-//                   expression? ")" statement ;
+//                   expression? ")" statement
+//                 | "for" "(" IDENTIFIER ( "," IDENTIFIER )? "in" expression ")"
+//                   statement ;
 // ifStmt         -> "if" "(" expression ")" statement ( "else" statement )? ;
 // printStmt      -> "print" expression ";" ;
 // returnStmt     -> "return" expression? ";" ;
+// throwStmt      -> "throw" expression ";" ;
+// tryStmt        -> "try" block ( "catch" "(" IDENTIFIER ")" block )?
+//                   ( "finally" block )? ;
 // whileStmt      -> "while" "(" expression ")" statement ;
+// yieldStmt      -> "yield" expression? ";" ;
 //
 // expression     -> funExpr
 //                 | assignment ;
 // funExpr        -> "fun" "(" parameters? ")" block ;
-//assignment     → ( call "." )? IDENTIFIER "=" assignment
-//               | logic_or ;
+//assignment     → ( call "." )? IDENTIFIER assignOp assignment
+//               | call "[" expression "]" assignOp assignment
+//               | conditional ;
+// assignOp       -> "=" | "+=" | "-=" | "*=" | "/=" | "%=" ;
+// conditional    -> logicOr ( "?" expression ":" conditional )? ;
 // logicOr        -> logicAnd ( "or" logicAnd )* ;
 // logicAnd       -> equality ( "and" equality )* ;
 // equality       -> comparison ( ( "!=" | "==" ) comparison )* ;
-// comparison     -> term ( ( ">" | ">=" | "<" | "<=" ) term )* ;
+// comparison     -> bitOr ( ( ">" | ">=" | "<" | "<=" ) bitOr )* ;
+// bitOr          -> bitXor ( "|" bitXor )* ;
+// bitXor         -> bitAnd ( "^" bitAnd )* ;
+// bitAnd         -> shift ( "&" shift )* ;
+// shift          -> term ( ( "<<" | ">>" ) term )* ;
 // term           -> factor ( ( "-" | "+" ) factor )* ;
-// factor         -> unary ( ( "/" | "*" ) unary )* ;
-// unary          -> ( "!" | "-" ) unary | call ;
-// call           → primary ( "(" arguments? ")" | "." IDENTIFIER )* ;
+// factor         -> unary ( ( "/" | "div" | "*" | "%" ) unary )* ;
+// unary          -> ( "!" | "-" | "~" ) unary | power ;
+// power          -> update ( "**" unary )? ;
+// update         -> ( "++" | "--" ) call | call ( "++" | "--" )? ;
+// call           → primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]" )* ;
 // arguments      -> expression ( "," expression )* ;
 // primary        -> NUMBER | STRING | "true" | "false" | "nil"
-//                 | "(" expression ")"
-//                 | IDENTIFIER ;
+//                 | interpolation | "(" expression ")"
+//                 | IDENTIFIER | "super" "." IDENTIFIER | list | map ;
+// interpolation  -> ( INTERPOLATION expression )+ STRING ;
+// list           -> "[" ( expression ( "," expression )* )? "]" ;
+// map            -> "{" ( expression ":" expression ( "," expression ":" expression )* )? "}" ;
 //
 
 type parser struct {
 	tokens  []*tokenObj
 	current int
 	errs    []error //multiple error support
-	inLoop  int     // correctness check for statement which should be inside loop
 }
 
 func NewParser(tokens []*tokenObj) *parser {
-	p := &parser{tokens, 0, make([]error, 0), 0}
+	p := &parser{tokens, 0, make([]error, 0)}
 	return p
 }
 
@@ -85,6 +109,16 @@ func (p *parser) match(toks ...token) bool {
 	return false
 }
 
+// matchWord is match for an identifier that is only special in one place ,
+// like the as of an import
+func (p *parser) matchWord(word string) bool {
+	if p.check(Identifier) && p.peek().lexeme == word {
+		p.advance()
+		return true
+	}
+	return false
+}
+
 func (p *parser) advance() *tokenObj {
 	if !p.atEnd() {
 		p.current++
@@ -100,6 +134,14 @@ func (p *parser) peek() *tokenObj {
 	return p.tokens[p.current]
 }
 
+// peekAt is the token n ahead of peek , EOF past the end
+func (p *parser) peekAt(n int) *tokenObj {
+	if p.current+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.current+n]
+}
+
 func (p *parser) prev() *tokenObj {
 	return p.tokens[p.current-1]
 }
@@ -117,41 +159,45 @@ func (p *parser) consume(expected token, msg string) *tokenObj {
 	if p.check(expected) {
 		return p.advance()
 	}
-	p.primaryError(p.peek(), msg)
+	p.primaryError(p.peek(), P_EXPECTED_TOKEN, msg)
 	return nil
 }
 
-//error type for parsing
-type ParsingError string
-
-func (e ParsingError) Error() string {
-	return string(e)
+// spanned sets the span of e , from start to the last consumed token
+func (p *parser) spanned(e Expr, start Span) Expr {
+	e.setPos(start.to(p.prev().span))
+	return e
 }
 
 //primary error that stop parse immediately and panic
-func (p *parser) primaryError(t *tokenObj, msg string) {
-	e := ParsingError(errorAtToken(t, msg))
-	p.errs = append(p.errs, e)
-	panic(e)
+func (p *parser) primaryError(t *tokenObj, code, msg string) {
+	if t.tok == Illegal {
+		panic(t.literal) // already reported by the scanner , just sync
+	}
+	d := diagnosticAt(t, code, msg)
+	p.errs = append(p.errs, d)
+	panic(d)
 }
 
-//errors that dont stop parse
-func (p *parser) yerror(t *tokenObj, msg string) {
-	e := ParsingError(errorAtToken(t, msg))
-	p.errs = append(p.errs, e)
+//errors that dont stop parse , the diagnostic is returned for notes
+func (p *parser) yerror(t *tokenObj, code, msg string) *Diagnostic {
+	d := diagnosticAt(t, code, msg)
+	if t.tok != Illegal {
+		p.errs = append(p.errs, d)
+	}
+	return d
 }
 
 //https://craftinginterpreters.com/parsing-expressions.html#panic-mode-error-recovery
 //https://craftinginterpreters.com/parsing-expressions.html#synchronizing-a-recursive-descent-parser
 func (p *parser) sync() {
-	fmt.Println("sync")
 	p.advance()
 	for !p.atEnd() {
 		if p.prev().tok == Semicolon { //pass 这条解析错误的语句
 			return
 		}
 		switch p.peek().tok { // or any of these start keyword
-		case Class, Fun, Var, For, If, While, Print, Return:
+		case Class, Fun, Var, For, If, While, Print, Return, Throw, Try, Import, From, Yield:
 			return
 		}
 		p.advance()
@@ -172,8 +218,16 @@ func (p *parser) parse() (s []Stmt, errs []error) {
 	return s, p.errs
 }
 
+// classDecl      -> "class" IDENTIFIER ( "<" IDENTIFIER )? "{" function* "}" ;
 func (p *parser) classDeclaration() Stmt {
 	name := p.consume(Identifier, "Expect class name.")
+
+	var superClass *VarExpr
+	if p.match(Less) {
+		p.consume(Identifier, "Expect superclass name.")
+		superClass = p.spanned(&VarExpr{name: p.prev()}, p.prev().span).(*VarExpr)
+	}
+
 	p.consume(LeftBrace, "Expect '{' before class body")
 
 	methods := []*FunStmt{}
@@ -184,6 +238,6 @@ func (p *parser) classDeclaration() Stmt {
 	return &ClassStmt{
 		name:       name,
 		methods:    methods,
-		superClass: nil,
+		superClass: superClass,
 	}
 }
@@ -0,0 +1,459 @@
+package lox
+
+// expression     -> funExpr
+//                 | assignment ;
+// funExpr        -> "fun" "(" parameters? ")" block ;
+// assignment     -> ( call "." )? IDENTIFIER assignOp assignment
+//				   | call "[" expression "]" assignOp assignment
+//				   | conditional ;
+// assignOp       -> "=" | "+=" | "-=" | "*=" | "/=" | "%=" ;
+// conditional    -> logicOr ( "?" expression ":" conditional )? ;
+// logicOr        -> logicAnd ( "or" logicAnd )* ;
+// logicAnd       -> equality ( "and" equality )* ;
+// equality       -> comparison ( ( "!=" | "==" ) comparison )* ;
+// comparison     -> bitOr ( ( ">" | ">=" | "<" | "<=" ) bitOr )* ;
+// bitOr          -> bitXor ( "|" bitXor )* ;
+// bitXor         -> bitAnd ( "^" bitAnd )* ;
+// bitAnd         -> shift ( "&" shift )* ;
+// shift          -> term ( ( "<<" | ">>" ) term )* ;
+// term           -> factor ( ( "-" | "+" ) factor )* ;
+// factor         -> unary ( ( "/" | "div" | "*" | "%" ) unary )* ;
+// unary          -> ( "!" | "-" | "~" ) unary | power ;
+// power          -> update ( "**" unary )? ;
+// update         -> ( "++" | "--" ) call | call ( "++" | "--" )? ;
+// call			  -> primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]" )* ;
+// arguments      -> expression ( "," expression )* ;
+// primary        -> NUMBER | STRING | "true" | "false" | "nil"
+//                 | interpolation | "(" expression ")"
+//                 | IDENTIFIER | "super" "." IDENTIFIER | list | map ;
+
+//priority related design , BNF method
+func (p *parser) assignment() Expr {
+	expr := p.conditional()
+	if p.match(Equal, PlusEqual, MinusEqual, StarEqual, SlashEqual, PercentEqual) {
+		equals := p.prev()
+		value := p.assignment()
+		var op *tokenObj
+		if equals.tok != Equal {
+			op = equals
+		}
+		if set := p.assignTo(expr, value, op, false); set != nil {
+			return p.spanned(set, expr.pos())
+		}
+		p.invalidTarget(equals, expr)
+	}
+	return expr
+}
+
+// assignTo turns the target expr into the node that assigns to it , nil when
+// expr can't be assigned to. op is nil for plain = , value is nil for ++ and --
+func (p *parser) assignTo(expr, value Expr, op *tokenObj, postfix bool) Expr {
+	if ev, ok := expr.(*VarExpr); ok {
+		name := ev.name
+		return &AssignExpr{name: name, value: value, op: op, postfix: postfix}
+	} else if get, ok := expr.(*GetExpr); ok {
+		return &SetExpr{
+			name:    get.name,
+			object:  get.object,
+			vlue:    value,
+			op:      op,
+			postfix: postfix,
+		}
+	} else if get, ok := expr.(*IndexGetExpr); ok {
+		return &IndexSetExpr{
+			object:  get.object,
+			bracket: get.bracket,
+			index:   get.index,
+			value:   value,
+			op:      op,
+			postfix: postfix,
+		}
+	}
+	return nil
+}
+
+func (p *parser) invalidTarget(t *tokenObj, expr Expr) {
+	d := p.yerror(t, P_INVALID_ASSIGNMENT, "invalid assignment target")
+	d.Span = expr.pos()
+	d.note("only variables, properties and indexes can be assigned to")
+}
+
+// conditional -> logicOr ( "?" expression ":" conditional )? ;
+// right associative , a ? b : c ? d : e is a ? b : (c ? d : e)
+func (p *parser) conditional() Expr {
+	expr := p.or()
+	if p.match(Question) {
+		op := p.prev()
+		then := p.expression()
+		p.consume(Colon, "expected ':' after then branch of conditional expression")
+		els := p.conditional()
+		return p.spanned(&TernaryExpr{operator: op, op1: expr, op2: then, op3: els}, expr.pos())
+	}
+	return expr
+}
+
+func (p *parser) or() Expr {
+	expr := p.and() //precedence
+	for p.match(Or) {
+		op := p.prev()
+		right := p.and() //precedence ,  a&&b || c&&d , avoid exec b||c
+		expr = p.spanned(&LogicalExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+func (p *parser) and() Expr { //a==b && c==d , avoid b&&c
+	expr := p.equality()
+	for p.match(And) {
+		op := p.prev()
+		right := p.equality()
+		expr = p.spanned(&LogicalExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// equality -> comparison ( ( "!=" | "==" ) comparison )* ;
+func (p *parser) equality() Expr { // a>=c == b<=d , avoid c==b
+	expr := p.comparison()
+	for p.match(BangEqual, EqualEqual) {
+		op := p.prev()
+		right := p.comparison()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// comparison -> bitOr ( ( ">" | ">=" | "<" | "<=" ) bitOr )* ;
+func (p *parser) comparison() Expr { // 1+2 >= 3-4
+	expr := p.bitOr()
+	for p.match(Greater, GreaterEqual, Less, LessEqual) {
+		op := p.prev()
+		right := p.bitOr()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// bitOr -> bitXor ( "|" bitXor )* ;
+// the bitwise operators bind tighter than comparisons , so x & 1 == 0 is
+// (x & 1) == 0
+func (p *parser) bitOr() Expr {
+	expr := p.bitXor()
+	for p.match(Pipe) {
+		op := p.prev()
+		right := p.bitXor()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// bitXor -> bitAnd ( "^" bitAnd )* ;
+func (p *parser) bitXor() Expr {
+	expr := p.bitAnd()
+	for p.match(Caret) {
+		op := p.prev()
+		right := p.bitAnd()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// bitAnd -> shift ( "&" shift )* ;
+func (p *parser) bitAnd() Expr {
+	expr := p.shift()
+	for p.match(Amp) {
+		op := p.prev()
+		right := p.shift()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// shift -> term ( ( "<<" | ">>" ) term )* ;
+func (p *parser) shift() Expr {
+	expr := p.term()
+	for p.match(LessLess, GreaterGreater) {
+		op := p.prev()
+		right := p.term()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// term ->  factor ( ( "-" | "+" ) factor )* ;
+//term refers to the two terms to the left and right of the operator in addition and subtraction , like (1+2) +(3+4)
+func (p *parser) term() Expr {
+	expr := p.factor()
+	for p.match(Plus, Minus) {
+		op := p.prev()
+		right := p.factor()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// factor -> unary ( ( "/" | "div" | "*" | "%" ) unary )* ;
+//括号的运算优先级是比乘除法还要高的，所以我们新增一个非终结符factor（因子）
+func (p *parser) factor() Expr {
+	expr := p.unary() //  -1 * -2
+	for p.match(Slash, Div, Star, Percent) {
+		op := p.prev()
+		right := p.unary()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// unary -> ( "!" | "-" | "~" ) unary
+//        | power ;
+func (p *parser) unary() Expr {
+	if p.match(Bang, Minus, Tilde) { //--2  !!true
+		op := p.prev()
+		right := p.unary()
+		return p.spanned(&UnaryExpr{operator: op, right: right}, op.span)
+	}
+	return p.power()
+}
+
+// power -> update ( "**" unary )? ;
+// right associative and tighter than unary on its left , -2 ** 2 is -(2 ** 2)
+// and 2 ** -1 is 0.5
+func (p *parser) power() Expr {
+	expr := p.update()
+	if p.match(StarStar) {
+		op := p.prev()
+		right := p.unary()
+		expr = p.spanned(&BinaryExpr{operator: op, left: expr, right: right}, expr.pos())
+	}
+	return expr
+}
+
+// update -> ( "++" | "--" ) call | call ( "++" | "--" )? ;
+// ++x produces the new value and x++ the old one
+func (p *parser) update() Expr {
+	if p.match(PlusPlus, MinusMinus) {
+		op := p.prev()
+		target := p.call()
+		if set := p.assignTo(target, nil, op, false); set != nil {
+			return p.spanned(set, op.span)
+		}
+		p.invalidTarget(op, target)
+		return target
+	}
+	expr := p.call()
+	if p.match(PlusPlus, MinusMinus) {
+		op := p.prev()
+		if set := p.assignTo(expr, nil, op, true); set != nil {
+			return p.spanned(set, expr.pos())
+		}
+		p.invalidTarget(op, expr)
+	}
+	return expr
+}
+
+// call			  -> primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]" )* ;
+// arguments      -> expression ( "," expression )* ;
+// primary        -> NUMBER | STRING | "true" | "false" | "nil"
+//                 | interpolation | "(" expression ")"
+//                 | IDENTIFIER | "super" "." IDENTIFIER | list | map ;
+func (p *parser) call() Expr {
+	expr := p.primary() //主表达式 , 理解为一个值 , 或者产生值的 主体
+	for {
+		if p.match(LeftParen) {
+			expr = p.finishCall(expr)
+		} else if p.match(Dot) {
+			name := p.consume(Identifier, "Expect property name after '.'.")
+			expr = p.spanned(&GetExpr{
+				name:   name,
+				object: expr,
+			}, expr.pos())
+		} else if p.match(LeftBracket) {
+			bracket := p.prev()
+			index := p.expression()
+			p.consume(RightBracket, "expected ']' after index")
+			expr = p.spanned(&IndexGetExpr{
+				object:  expr,
+				bracket: bracket,
+				index:   index,
+			}, expr.pos())
+		} else {
+			break
+		}
+	}
+	return expr
+}
+
+//immediately call the funExpr produce
+func (p *parser) finishCall(expr Expr) Expr {
+	args := make([]Expr, 0)
+	if !p.check(RightParen) {
+		// parse params
+		for {
+			if len(args) >= 255 {
+				p.yerror(p.peek(), P_TOO_MANY_ARGUMENTS, "can't have more than 255 arguments")
+			}
+			args = append(args, p.expression())
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	// for error display
+	paren := p.consume(RightParen, "expected ')' after arguments")
+
+	//callee : 被 call 的人 , 先被 call 产生值作为参数
+	return p.spanned(&CallExpr{callee: expr, paren: paren, args: args}, expr.pos())
+}
+
+// primary -> NUMBER | STRING | "true" | "false" | "nil"
+//          | "(" expression ")" ;
+func (p *parser) primary() Expr {
+	start := p.peek().span
+	switch {
+	case p.match(False):
+		return p.spanned(&LiteralExpr{value: false}, start)
+	case p.match(True):
+		return p.spanned(&LiteralExpr{value: true}, start)
+	case p.match(Nil):
+		return p.spanned(&LiteralExpr{value: nil}, start)
+	case p.match(Number, String):
+		return p.spanned(&LiteralExpr{value: p.prev().literal}, start)
+	case p.match(Interpolation):
+		return p.spanned(p.interpolation(), start)
+	case p.match(This):
+		return p.spanned(&ThisExpr{keyword: p.prev()}, start)
+	case p.match(Super):
+		keyword := p.prev()
+		p.consume(Dot, "Expect '.' after 'super'.")
+		method := p.consume(Identifier, "Expect superclass method name.")
+		return p.spanned(&SuperExpr{keyword: keyword, method: method}, start)
+	case p.match(Identifier):
+		return p.spanned(&VarExpr{name: p.prev()}, start)
+	case p.match(LeftParen):
+		expr := p.expression()
+		p.consume(RightParen, "expected enclosing ')' after expression")
+		return p.spanned(&GroupingExpr{expression: expr}, start)
+	case p.match(LeftBracket):
+		return p.list()
+	case p.match(LeftBrace):
+		return p.hashMap()
+	}
+	p.primaryError(p.peek(), P_EXPECTED_EXPRESSION, "expected expression")
+	return nil
+}
+
+// interpolation  -> ( INTERPOLATION expression )+ STRING ;
+// the scanner splits "a ${x} b" into the segments around each expression
+func (p *parser) interpolation() Expr {
+	parts := make([]Expr, 0)
+	segment := p.prev()
+	for {
+		if s := segment.literal.(string); s != "" {
+			parts = append(parts, p.spanned(&LiteralExpr{value: s}, segment.span))
+		}
+		if segment.tok == String {
+			return &InterpolationExpr{parts: parts}
+		}
+		parts = append(parts, p.expression())
+		if !p.match(Interpolation) {
+			p.consume(String, "expected '}' after interpolated expression")
+		}
+		segment = p.prev()
+	}
+}
+
+// list           -> "[" ( expression ( "," expression )* )? "]" ;
+func (p *parser) list() Expr {
+	bracket := p.prev()
+	elements := make([]Expr, 0)
+	if !p.check(RightBracket) {
+		for {
+			elements = append(elements, p.expression())
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	p.consume(RightBracket, "expected ']' after list elements")
+	return p.spanned(&ListExpr{bracket: bracket, elements: elements}, bracket.span)
+}
+
+// map            -> "{" ( expression ":" expression ( "," expression ":" expression )* )? "}" ;
+func (p *parser) hashMap() Expr {
+	brace := p.prev()
+	keys := make([]Expr, 0)
+	values := make([]Expr, 0)
+	if !p.check(RightBrace) {
+		for {
+			keys = append(keys, p.expression())
+			p.consume(Colon, "expected ':' after map key")
+			values = append(values, p.expression())
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	p.consume(RightBrace, "expected '}' after map entries")
+	return p.spanned(&MapExpr{brace: brace, keys: keys, values: values}, brace.span)
+}
+
+// parse expr part
+
+// expression     -> funExpr
+//                 | assignment ;
+// funExpr        -> "fun" "(" parameters? ")" block ;
+// assignment     -> ( call "." )? IDENTIFIER assignOp assignment
+//				   | call "[" expression "]" assignOp assignment
+//				   | conditional ;
+// assignOp       -> "=" | "+=" | "-=" | "*=" | "/=" | "%=" ;
+// conditional    -> logicOr ( "?" expression ":" conditional )? ;
+// logicOr        -> logicAnd ( "or" logicAnd )* ;
+// logicAnd       -> equality ( "and" equality )* ;
+// equality       -> comparison ( ( "!=" | "==" ) comparison )* ;
+// comparison     -> bitOr ( ( ">" | ">=" | "<" | "<=" ) bitOr )* ;
+// bitOr          -> bitXor ( "|" bitXor )* ;
+// bitXor         -> bitAnd ( "^" bitAnd )* ;
+// bitAnd         -> shift ( "&" shift )* ;
+// shift          -> term ( ( "<<" | ">>" ) term )* ;
+// term           -> factor ( ( "-" | "+" ) factor )* ;
+// factor         -> unary ( ( "/" | "div" | "*" | "%" ) unary )* ;
+// unary          -> ( "!" | "-" | "~" ) unary | power ;
+// power          -> update ( "**" unary )? ;
+// update         -> ( "++" | "--" ) call | call ( "++" | "--" )? ;
+// call			  -> primary ( "(" arguments? ")" | "." IDENTIFIER | "[" expression "]" )* ;
+// arguments      -> expression ( "," expression )* ;
+// primary        -> NUMBER | STRING | "true" | "false" | "nil"
+//                 | interpolation | "(" expression ")"
+//                 | IDENTIFIER | "super" "." IDENTIFIER | list | map ;
+func (p *parser) expression() Expr {
+	if p.match(Fun) {
+		return p.funExpr()
+	}
+	return p.assignment()
+}
+
+//// funExpr        -> "fun" "(" parameters? ")" block ;
+// is also an expression , it produce what ? a closure
+//TODO; code just a tool for implement logic , what most important is logic
+func (p *parser) funExpr() Expr {
+	start := p.prev().span
+	p.consume(LeftParen, "expected '(' after 'fun'")
+	params := make([]*tokenObj, 0)
+	if !p.check(RightParen) {
+		// parse param name
+		for {
+			if len(params) >= 255 {
+				p.yerror(p.peek(), P_TOO_MANY_PARAMETERS, "can't have more than 255 parameters")
+			}
+			params = append(params, p.consume(Identifier, "expected parameter name"))
+			if !p.match(Comma) {
+				break
+			}
+		}
+	}
+	p.consume(RightParen, "expected ')' after parameters")
+	p.consume(LeftBrace, "expected '{' after anonymous function signature")
+	// parse block
+	body := p.block()
+	return p.spanned(&FunExpr{params: params, body: body}, start)
+}
@@ -1,10 +1,10 @@
-package main
+package lox
 
 // parse single stmt ast-tree from tokens
 func (p *parser) declaration() (s Stmt) {
 	defer func() {
 		if e := recover(); e != nil {
-			_ = e.(ParsingError) // Panic for other errors
+			_ = e.(*Diagnostic) // Panic for other errors
 			/*
 				var a interface{}
 				a=1
@@ -21,6 +21,7 @@ func (p *parser) declaration() (s Stmt) {
 	// program        -> declaration* EOF ;
 	//
 	// declaration    -> funDecl
+	//                 | importDecl
 	//                 | lambdaCall
 	//                 | varDecl
 	//                 | statement ;
@@ -36,6 +37,9 @@ func (p *parser) declaration() (s Stmt) {
 	if p.match(Var) {
 		return p.varDecl()
 	}
+	if p.match(Import, From) {
+		return p.importDecl()
+	}
 	return p.statement()
 }
 
@@ -48,7 +52,7 @@ func (p *parser) funDecl(kind string) Stmt {
 	if !p.check(RightParen) {
 		for {
 			if len(params) >= 255 {
-				p.yerror(p.peek(), "can't have more than 255 parameters")
+				p.yerror(p.peek(), P_TOO_MANY_PARAMETERS, "can't have more than 255 parameters")
 			}
 			params = append(params, p.consume(Identifier, "expected parameter name"))
 			if !p.match(Comma) {
@@ -75,6 +79,36 @@ func (p *parser) varDecl() Stmt {
 	return &VarStmt{name: name, init: init}
 }
 
+// import "lib.glx" as lib; or from "lib.glx" import a, b as c;
+// as is only special here , it stays a valid variable name
+func (p *parser) importDecl() Stmt {
+	s := &ImportStmt{keyword: p.prev()}
+	s.path = p.consume(String, "expected module path after '"+s.keyword.lexeme+"'")
+	if s.keyword.tok == Import {
+		if !p.matchWord("as") {
+			p.primaryError(p.peek(), P_EXPECTED_TOKEN, "expected 'as' after module path")
+		}
+		s.name = p.consume(Identifier, "expected module name after 'as'")
+		p.consume(Semicolon, "expected ';' after import")
+		return s
+	}
+	p.consume(Import, "expected 'import' after module path")
+	for {
+		name := p.consume(Identifier, "expected name to import")
+		alias := name
+		if p.matchWord("as") {
+			alias = p.consume(Identifier, "expected name after 'as'")
+		}
+		s.exports = append(s.exports, name)
+		s.aliases = append(s.aliases, alias)
+		if !p.match(Comma) {
+			break
+		}
+	}
+	p.consume(Semicolon, "expected ';' after import")
+	return s
+}
+
 /* difference between statement and expr ?
 
 almost the same , part of ast-tree , separate for convenient
@@ -88,7 +122,10 @@ almost the same , part of ast-tree , separate for convenient
 //                 | ifStmt
 //                 | printStmt
 //                 | returnStmt
+//                 | throwStmt
+//                 | tryStmt
 //                 | whileStmt
+//                 | yieldStmt
 //				   | block ;
 func (p *parser) statement() Stmt {
 	if p.match(Break) {
@@ -109,9 +146,18 @@ func (p *parser) statement() Stmt {
 	if p.match(Return) {
 		return p.returnStatement()
 	}
+	if p.match(Throw) {
+		return p.throwStatement()
+	}
+	if p.match(Try) {
+		return p.tryStatement()
+	}
 	if p.match(While) {
 		return p.whileStatement()
 	}
+	if p.match(Yield) {
+		return p.yieldStatement()
+	}
 	if p.match(LeftBrace) {
 		return &BlockStmt{list: p.block()}
 	}
@@ -119,26 +165,23 @@ func (p *parser) statement() Stmt {
 }
 
 func (p *parser) breakStatement() Stmt {
-	key := p.prev()
-	// sanity check
-	if p.inLoop < 1 {
-		p.primaryError(key, "expected inside the loop")
-	}
+	key := p.prev() // the resolver checks that it is inside a loop
 	p.consume(Semicolon, "expected ';' after break")
 	return &BreakStmt{keyword: key}
 }
 
 func (p *parser) continueStatement() Stmt {
 	key := p.prev()
-	if p.inLoop < 1 {
-		p.primaryError(key, "expected inside the loop")
-	}
 	p.consume(Semicolon, "expected ';' after continue")
 	return &ContinueStmt{keyword: key}
 }
 
 func (p *parser) forStatement() Stmt {
+	key := p.prev()
 	p.consume(LeftParen, "expected '(' after 'for'")
+	if p.check(Identifier) && (p.peekAt(1).tok == Comma || p.peekAt(1).tok == Identifier && p.peekAt(1).lexeme == "in") {
+		return p.forInStatement(key)
+	}
 
 	var initial Stmt
 	switch {
@@ -162,19 +205,14 @@ func (p *parser) forStatement() Stmt {
 	}
 	p.consume(RightParen, "expected ')' after for clauses")
 
-	p.inLoop += 1
 	body := p.statement() //may be a block statement or other one line code
-	p.inLoop -= 1
 
 	// all of these three may not exist , nested set since  , TODO;beautiful design
-	if incr != nil {
-		body = &BlockStmt{list: []Stmt{
-			body,
-			&ExprStmt{expression: incr}}}
-	}
-	if cond != nil {
-		body = &WhileStmt{condition: cond, body: body}
+	// incr stays on the loop itself so that continue does not skip it
+	if cond == nil {
+		cond = &LiteralExpr{value: true} // for (;;)
 	}
+	body = &WhileStmt{keyword: key, condition: cond, body: body, increment: incr}
 	if initial != nil {
 		body = &BlockStmt{list: []Stmt{
 			initial,
@@ -183,6 +221,23 @@ func (p *parser) forStatement() Stmt {
 	return body
 }
 
+// for (x in xs) or for (k, v in m) , in is only special here
+func (p *parser) forInStatement(key *tokenObj) Stmt {
+	s := &ForInStmt{keyword: key}
+	s.name = p.consume(Identifier, "expected loop variable")
+	if p.match(Comma) {
+		s.key = s.name
+		s.name = p.consume(Identifier, "expected variable name after ','")
+	}
+	if !p.matchWord("in") {
+		p.primaryError(p.peek(), P_EXPECTED_TOKEN, "expected 'in' after loop variables")
+	}
+	s.iterable = p.expression()
+	p.consume(RightParen, "expected ')' after for-in iterable")
+	s.body = p.statement()
+	return s
+}
+
 //TODO;readable code standard ,  code just a tool for implement logic , what most important is logic
 func (p *parser) ifStatement() Stmt {
 	p.consume(LeftParen, "expected '(' after 'if'")
@@ -216,14 +271,53 @@ func (p *parser) returnStatement() Stmt {
 	return &ReturnStmt{keyword: k, value: val}
 }
 
+func (p *parser) throwStatement() Stmt {
+	k := p.prev()
+	val := p.expression()
+	p.consume(Semicolon, "expected ';' after thrown value")
+	return &ThrowStmt{keyword: k, value: val}
+}
+
+// try { } catch (e) { } finally { } , at least one of catch and finally
+func (p *parser) tryStatement() Stmt {
+	s := &TryStmt{keyword: p.prev()}
+	p.consume(LeftBrace, "expected '{' after 'try'")
+	s.body = p.block()
+	if p.match(Catch) {
+		p.consume(LeftParen, "expected '(' after 'catch'")
+		s.name = p.consume(Identifier, "expected error variable name")
+		p.consume(RightParen, "expected ')' after error variable")
+		p.consume(LeftBrace, "expected '{' before catch body")
+		s.catch = p.block()
+	}
+	if p.match(Finally) {
+		p.consume(LeftBrace, "expected '{' after 'finally'")
+		s.finally = p.block()
+	}
+	if s.name == nil && s.finally == nil {
+		p.primaryError(p.peek(), P_EXPECTED_TOKEN, "expected 'catch' or 'finally' after try block")
+	}
+	return s
+}
+
+// a function with a yield in its body is a generator
+func (p *parser) yieldStatement() Stmt {
+	k := p.prev()
+	var val Expr
+	if !p.check(Semicolon) {
+		val = p.expression()
+	}
+	p.consume(Semicolon, "expected ';' after yield value")
+	return &YieldStmt{keyword: k, value: val}
+}
+
 func (p *parser) whileStatement() Stmt {
+	key := p.prev()
 	p.consume(LeftParen, "expected '(' after while")
 	expr := p.expression()
 	p.consume(RightParen, "expected ')' after while condition")
-	p.inLoop += 1
 	body := p.statement()
-	p.inLoop -= 1
-	return &WhileStmt{condition: expr, body: body}
+	return &WhileStmt{keyword: key, condition: expr, body: body}
 }
 
 // funDecl        -> "fun" function ;
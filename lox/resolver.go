@@ -0,0 +1,475 @@
+package lox
+
+type FunctionType uint
+type ClassType uint
+
+const (
+	FT_NONE = iota
+	FT_FUNCTION
+	FT_INITIALIZER
+	FT_METHOD
+)
+
+const (
+	CT_NONE = iota
+	CT_CLASS
+	CT_SUBCLASS
+)
+
+// NewResolver records the scope distance of every resolved variable on the
+// variable's node , the AST is only read by the interpreter that parsed it
+func NewResolver() *Resolver {
+	return &Resolver{
+		scopes:          make([]map[string]bool, 0),
+		currentFunction: 0,
+		currentClass:    0,
+	}
+}
+
+type Resolver struct {
+	scopes          []map[string]bool
+	currentFunction FunctionType
+	currentClass    ClassType
+	loopDepth       int  // loops around the current statement , reset in functions
+	loopOutside     bool // a loop is around one of the enclosing functions
+	yields          bool // the current function has a yield
+
+	errs []error
+}
+
+// error records a diagnostic and goes on , so that every error is reported in
+// one pass , the program is not run when there is one
+func (r *Resolver) error(t *tokenObj, code, msg string) *Diagnostic {
+	d := diagnosticAt(t, code, msg)
+	r.errs = append(r.errs, d)
+	return d
+}
+
+func (r *Resolver) resolve(stmts []Stmt) {
+	for _, stmt := range stmts {
+		r.resolveStmt(stmt)
+	}
+}
+
+func (r *Resolver) resolveStmt(stmt Stmt) {
+	stmt.accept(r)
+}
+
+func (r *Resolver) visitBlockStmt(s *BlockStmt) {
+	r.beginScope()
+	r.resolve(s.list)
+	r.endScope()
+	return
+}
+
+func (r *Resolver) visitClassStmt(s *ClassStmt) {
+	enclosingClass := r.currentClass
+	r.currentClass = CT_CLASS
+	r.declare(s.name)
+	r.define(s.name)
+
+	if s.superClass != nil {
+		if s.name.lexeme == s.superClass.name.lexeme {
+			r.error(s.superClass.name, S_INHERIT_ITSELF, "A class can't inherit from itself.")
+		}
+		r.currentClass = CT_SUBCLASS
+		r.resolveExpr(s.superClass)
+
+		// methods close over an extra scope that holds "super"
+		r.beginScope()
+		r.scopePeek()["super"] = true
+	}
+
+	r.beginScope()
+
+	// scopes.peek().put("this", true);
+	scope := r.scopes[len(r.scopes)-1]
+	scope["this"] = true
+
+	for _, method := range s.methods {
+		decl := FT_METHOD
+		if method.name.lexeme == "init" {
+			decl = FT_INITIALIZER
+		}
+		method.generator = r.resolveFunction(method.params, method.body, FunctionType(decl))
+	}
+
+	r.endScope()
+	if s.superClass != nil {
+		r.endScope()
+	}
+	r.currentClass = enclosingClass
+	return
+}
+
+func (r *Resolver) visitExpressionStmt(s *ExprStmt) {
+	r.resolveExpr(s.expression)
+}
+
+func (r *Resolver) beginScope() {
+	r.scopes = append(r.scopes, make(map[string]bool))
+}
+
+func (r *Resolver) endScope() {
+	r.scopes = r.scopes[:len(r.scopes)-1]
+}
+
+func (r *Resolver) resolveExpr(expression Expr) {
+	expression.accept(r)
+}
+
+func (r *Resolver) visitFunctionStmt(s *FunStmt) {
+	// separate for edge case
+	/*
+		var a = 0
+		{
+			var a = a
+		}
+	*/
+	r.declare(s.name)
+	r.define(s.name)
+
+	s.generator = r.resolveFunction(s.params, s.body, FT_FUNCTION)
+}
+
+func (r *Resolver) visitIfStmt(s *IfStmt) {
+	r.resolveExpr(s.condition)
+	r.resolveStmt(s.block1)
+	if s.block2 != nil {
+		r.resolveStmt(s.block2)
+	}
+}
+
+func (r *Resolver) visitPrintStmt(s *PrintStmt) {
+	r.resolveExpr(s.expression)
+}
+
+func (r *Resolver) visitReturnStmt(s *ReturnStmt) {
+	if r.currentFunction == FT_NONE {
+		r.error(s.keyword, S_TOP_LEVEL_RETURN, "Can't return from top-level code.")
+	}
+
+	if s.value != nil {
+		if r.currentFunction == FT_INITIALIZER {
+			r.error(s.keyword, S_INITIALIZER_RETURN, "Can't return a value from an initializer.")
+		}
+
+		r.resolveExpr(s.value)
+	}
+	return
+}
+
+func (r *Resolver) visitImportStmt(s *ImportStmt) {
+	if s.name != nil {
+		r.declare(s.name)
+		r.define(s.name)
+	}
+	for _, alias := range s.aliases {
+		r.declare(alias)
+		r.define(alias)
+	}
+}
+
+func (r *Resolver) visitThrowStmt(s *ThrowStmt) {
+	r.resolveExpr(s.value)
+}
+
+// the catch variable shares the scope of the catch body , like parameters
+func (r *Resolver) visitTryStmt(s *TryStmt) {
+	r.beginScope()
+	r.resolve(s.body)
+	r.endScope()
+	if s.name != nil {
+		r.beginScope()
+		r.declare(s.name)
+		r.define(s.name)
+		r.resolve(s.catch)
+		r.endScope()
+	}
+	if s.finally != nil {
+		r.beginScope()
+		r.resolve(s.finally)
+		r.endScope()
+	}
+}
+
+func (r *Resolver) visitYieldStmt(s *YieldStmt) {
+	switch r.currentFunction {
+	case FT_NONE:
+		r.error(s.keyword, S_TOP_LEVEL_YIELD, "Can't yield from top-level code.")
+	case FT_INITIALIZER:
+		r.error(s.keyword, S_INITIALIZER_YIELD, "Can't yield from an initializer.")
+	}
+	r.yields = true
+	if s.value != nil {
+		r.resolveExpr(s.value)
+	}
+}
+
+func (r *Resolver) visitVarStmt(s *VarStmt) {
+	r.declare(s.name)
+	if s.init != nil {
+		r.resolveExpr(s.init)
+	}
+	r.define(s.name)
+	return
+}
+
+func (r *Resolver) visitWhileStmt(s *WhileStmt) {
+	r.resolveExpr(s.condition)
+	r.loopDepth++
+	r.resolveStmt(s.body) //block stmt
+	r.loopDepth--
+	if s.increment != nil {
+		r.resolveExpr(s.increment)
+	}
+	return
+}
+
+// the loop variables get a scope of their own , fresh for every iteration
+func (r *Resolver) visitForInStmt(s *ForInStmt) {
+	r.resolveExpr(s.iterable)
+	r.loopDepth++
+	r.beginScope()
+	if s.key != nil {
+		r.declare(s.key)
+		r.define(s.key)
+	}
+	r.declare(s.name)
+	r.define(s.name)
+	r.resolveStmt(s.body)
+	r.endScope()
+	r.loopDepth--
+}
+
+func (r *Resolver) visitBreakStmt(s *BreakStmt) {
+	if r.loopDepth == 0 {
+		r.loopNote(r.error(s.keyword, S_BREAK_OUTSIDE_LOOP, "Can't use 'break' outside of a loop."))
+	}
+}
+
+func (r *Resolver) visitContinueStmt(s *ContinueStmt) {
+	if r.loopDepth == 0 {
+		r.loopNote(r.error(s.keyword, S_CONTINUE_OUTSIDE_LOOP, "Can't use 'continue' outside of a loop."))
+	}
+}
+
+func (r *Resolver) loopNote(d *Diagnostic) {
+	if r.loopOutside {
+		d.note("the loop is outside of the function, which can only be left with return")
+	}
+}
+
+func (r *Resolver) visitAssignExpr(e *AssignExpr) {
+	if e.value != nil { // nil for ++ and --
+		r.resolveExpr(e.value)
+	}
+	r.resolveLocal(&e.at, e.name)
+	return
+}
+
+func (r *Resolver) visitBinaryExpr(e *BinaryExpr) {
+	r.resolveExpr(e.left)
+	r.resolveExpr(e.right)
+	return
+}
+
+func (r *Resolver) visitCallExpr(e *CallExpr) {
+	r.resolveExpr(e.callee)
+	for _, argument := range e.args {
+		r.resolveExpr(argument)
+	}
+	return
+}
+
+func (r *Resolver) visitGetExpr(e *GetExpr) {
+	r.resolveExpr(e.object)
+}
+func (r *Resolver) visitSetExpr(e *SetExpr) {
+	r.resolveExpr(e.object)
+	if e.vlue != nil {
+		r.resolveExpr(e.vlue)
+	}
+}
+
+func (r *Resolver) visitIndexGetExpr(e *IndexGetExpr) {
+	r.resolveExpr(e.object)
+	r.resolveExpr(e.index)
+}
+
+func (r *Resolver) visitIndexSetExpr(e *IndexSetExpr) {
+	r.resolveExpr(e.object)
+	r.resolveExpr(e.index)
+	if e.value != nil {
+		r.resolveExpr(e.value)
+	}
+}
+
+func (r *Resolver) visitListExpr(e *ListExpr) {
+	for _, element := range e.elements {
+		r.resolveExpr(element)
+	}
+}
+
+func (r *Resolver) visitMapExpr(e *MapExpr) {
+	for i := range e.keys {
+		r.resolveExpr(e.keys[i])
+		r.resolveExpr(e.values[i])
+	}
+}
+
+func (r *Resolver) visitFunExpr(e *FunExpr) {
+	e.generator = r.resolveFunction(e.params, e.body, FT_FUNCTION)
+}
+
+func (r *Resolver) visitGroupingExpr(e *GroupingExpr) {
+	r.resolveExpr(e.expression)
+	return
+}
+
+func (r *Resolver) visitLiteralExpr(e *LiteralExpr) {
+	return
+}
+
+func (r *Resolver) visitInterpolationExpr(e *InterpolationExpr) {
+	for _, part := range e.parts {
+		r.resolveExpr(part)
+	}
+}
+
+func (r *Resolver) visitLogicalExpr(e *LogicalExpr) {
+	r.resolveExpr(e.left)
+	r.resolveExpr(e.right)
+	return
+}
+
+func (r *Resolver) visitTernaryExpr(e *TernaryExpr) {
+	r.resolveExpr(e.op1)
+	r.resolveExpr(e.op2)
+	r.resolveExpr(e.op3)
+}
+
+func (r *Resolver) visitThisExpr(e *ThisExpr) {
+	if r.currentClass == CT_NONE {
+		r.error(e.keyword, S_THIS_OUTSIDE_CLASS, "Can't use 'this' outside of a class.")
+		return
+	}
+	r.resolveLocal(&e.at, e.keyword)
+	return
+}
+
+//todo
+//func (r *Resolver) visitSetExpr(e *LiteralExpr) {
+//	r.resolveExpr(e.value)
+//	r.resolveExpr(e.object)
+//	return
+//}
+
+func (r *Resolver) visitSuperExpr(e *SuperExpr) {
+	if r.currentClass == CT_NONE {
+		r.error(e.keyword, S_SUPER_OUTSIDE_CLASS, "Can't use 'super' outside of a class.")
+		return
+	} else if r.currentClass != CT_SUBCLASS {
+		r.error(e.keyword, S_SUPER_NO_SUPERCLASS, "Can't use 'super' in a class with no superclass.")
+		return
+	}
+	r.resolveLocal(&e.at, e.keyword)
+	return
+}
+
+func (r *Resolver) visitUnaryExpr(e *UnaryExpr) {
+	r.resolveExpr(e.right)
+	return
+}
+func (r *Resolver) visitVariableExpr(e *VarExpr) {
+	if defined, ok := r.lookupCurrent(e.name.lexeme); ok && !defined {
+		r.error(e.name, S_OWN_INITIALIZER, "Can't read local variable in its own initializer.")
+	}
+	r.resolveLocal(&e.at, e.name)
+	return
+}
+
+func (r *Resolver) declare(name *tokenObj) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	scope := r.scopes[len(r.scopes)-1]
+	_, ok := scope[name.lexeme]
+	if ok {
+		r.error(name, S_ALREADY_DECLARED, "Already a variable with this name in this scope.")
+	}
+	scope[name.lexeme] = false
+}
+
+func (r *Resolver) define(name *tokenObj) {
+	if len(r.scopes) == 0 {
+		return
+	}
+
+	scope := r.scopes[len(r.scopes)-1]
+	scope[name.lexeme] = true
+}
+
+// resolveFunction is shared by FunStmt and the anonymous FunExpr , it
+// reports whether the body yields
+func (r *Resolver) resolveFunction(params []*tokenObj, body []Stmt, typee FunctionType) bool {
+	enclosingFunction, enclosingLoops, enclosingOutside := r.currentFunction, r.loopDepth, r.loopOutside
+	enclosingYields := r.yields
+	r.currentFunction = typee
+	r.yields = false
+	r.loopDepth = 0 // break can't leave a function body
+	r.loopOutside = enclosingOutside || enclosingLoops > 0
+
+	r.beginScope()
+
+	//resolve params
+	for _, tok := range params {
+		r.declare(tok)
+		r.define(tok)
+	}
+	//resolve body
+	r.resolve(body)
+
+	r.endScope()
+
+	r.currentFunction, r.loopDepth, r.loopOutside = enclosingFunction, enclosingLoops, enclosingOutside
+	yields := r.yields
+	r.yields = enclosingYields
+	return yields
+}
+
+//TODO
+func (r *Resolver) resolveLocal(at *resolved, name *tokenObj) {
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		scope := r.scopes[i]
+		if containKey(scope, name.lexeme) {
+			*at = resolved{depth: len(r.scopes) - 1 - i, local: true}
+			return
+		}
+	}
+
+}
+
+// lookupCurrent reports whether name is declared in the innermost scope and
+// whether its initializer has finished
+func (r *Resolver) lookupCurrent(name string) (defined bool, ok bool) {
+	if len(r.scopes) == 0 {
+		return false, false
+	}
+	defined, ok = r.scopePeek()[name]
+	return
+}
+
+func (r *Resolver) scopePeek() map[string]bool {
+	return r.scopes[len(r.scopes)-1]
+}
+
+func containKey(m map[string]bool, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func (s *LiteralExpr) reslove(r *Resolver) {
+	r.visitLiteralExpr(s)
+}
@@ -0,0 +1,550 @@
+package lox
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Scanner struct {
+	file    string      // for spans , may be empty
+	source  string      //input source code string
+	tokens  []*tokenObj //parsed tokens
+	start   int         //token lexeme start
+	current int
+	line    int
+	lineAt  int     // offset where the current line starts , for columns
+	errs    []error // every lexical error , scanning goes on after one
+
+	startLine, startLineAt int // line and lineAt of start
+
+	// open ${ in strings , each counts the { inside it so that the matching
+	// } goes back to the string
+	interps []int
+}
+
+func NewScanner(file, source string) *Scanner {
+	return &Scanner{
+		file:   file,
+		source: source,
+		tokens: make([]*tokenObj, 0),
+		line:   1,
+	}
+}
+
+// scan returns every token , with an Illegal token where there is an error ,
+// so that the parser can still report its own errors
+func (s *Scanner) scan() ([]*tokenObj, []error) {
+	//keep scan like a sliding window
+	for !s.atEnd() {
+		s.begin()
+		s.scanToken()
+	}
+
+	//put an EOF to indicate token end
+	s.begin()
+	if len(s.interps) > 0 {
+		s.interps = nil
+		s.report(L_UNTERMINATED_STRING, "unterminated ${ in string")
+	}
+	s.tokens = append(s.tokens, &tokenObj{tok: EOF, span: s.span()})
+	return s.tokens, s.errs
+}
+
+//scan single token
+func (s *Scanner) scanToken() {
+	ch := s.advance()
+	switch ch {
+	case '(':
+		s.token(LeftParen)
+	case ')':
+		s.token(RightParen)
+	case '{':
+		if n := len(s.interps); n > 0 {
+			s.interps[n-1]++
+		}
+		s.token(LeftBrace)
+	case '}':
+		if n := len(s.interps); n > 0 {
+			if s.interps[n-1] == 0 {
+				s.interps = s.interps[:n-1]
+				s.stringBody() // the rest of the string after ${...}
+				return
+			}
+			s.interps[n-1]--
+		}
+		s.token(RightBrace)
+	case '[':
+		s.token(LeftBracket)
+	case ']':
+		s.token(RightBracket)
+	case ',':
+		s.token(Comma)
+	case ':':
+		s.token(Colon)
+	case '.':
+		s.token(Dot)
+	case '-':
+		if s.match('-') {
+			s.token(MinusMinus)
+		} else if s.match('=') {
+			s.token(MinusEqual)
+		} else {
+			s.token(Minus)
+		}
+	case '?':
+		s.token(Question)
+	case '+':
+		if s.match('+') {
+			s.token(PlusPlus)
+		} else if s.match('=') {
+			s.token(PlusEqual)
+		} else {
+			s.token(Plus)
+		}
+	case ';':
+		s.token(Semicolon)
+	case '*':
+		if s.match('*') {
+			s.token(StarStar)
+		} else if s.match('=') {
+			s.token(StarEqual)
+		} else {
+			s.token(Star)
+		}
+	case '%':
+		if s.match('=') {
+			s.token(PercentEqual)
+		} else {
+			s.token(Percent)
+		}
+	case '&':
+		s.token(Amp)
+	case '|':
+		s.token(Pipe)
+	case '^':
+		s.token(Caret)
+	case '~':
+		s.token(Tilde)
+	case '!':
+		if s.match('=') {
+			s.token(BangEqual)
+		} else {
+			s.token(Bang)
+		}
+	case '=':
+		if s.match('=') {
+			s.token(EqualEqual)
+		} else {
+			s.token(Equal)
+		}
+	case '<':
+		if s.match('=') {
+			s.token(LessEqual)
+		} else if s.match('<') {
+			s.token(LessLess)
+		} else {
+			s.token(Less)
+		}
+	case '>':
+		if s.match('=') {
+			s.token(GreaterEqual)
+		} else if s.match('>') {
+			s.token(GreaterGreater)
+		} else {
+			s.token(Greater)
+		}
+	case '/':
+		//  match "//............."
+		if s.match('/') {
+			for s.peek() != '\n' && !s.atEnd() {
+				s.advance()
+			}
+		} else if s.match('*') {
+			s.fullComment()
+		} else if s.match('=') {
+			s.token(SlashEqual)
+		} else {
+			//match "/"
+			s.token(Slash)
+		}
+	case ' ', '\r', '\t':
+		//ignore
+		break
+	case '\n':
+		s.newline()
+	case '"':
+		s.stringLit()
+	default:
+		if isDigit(ch) {
+			s.number()
+		} else if isAlpha(ch) {
+			s.identifier()
+		} else if ch >= utf8.RuneSelf {
+			s.current = s.start
+			if r := s.advanceRune(); isLetter(r) {
+				s.identifier()
+			} else {
+				s.report(L_UNEXPECTED_CHAR, fmt.Sprintf("unexpected character '%c'", r))
+			}
+		} else {
+			s.report(L_UNEXPECTED_CHAR, fmt.Sprintf("unexpected character '%c'", ch))
+		}
+	}
+}
+
+func (s *Scanner) identifier() {
+	for !s.atEnd() {
+		r, size := utf8.DecodeRuneInString(s.source[s.current:])
+		if !isLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		s.current += size
+	}
+	text := s.source[s.start:s.current]
+	var t token
+	//case , keyword
+	if tok, ok := keywords[text]; ok {
+		t = tok
+	} else {
+		t = Identifier
+	}
+	s.token(t)
+}
+
+// number scans a number literal , integers are int64 and the rest float64
+//
+//	123 1_000_000 0xFF 0b1010 -> int64
+//	1.5 6.02e23 1e-9          -> float64
+//
+// underscores may only sit between two digits
+func (s *Scanner) number() {
+	s.current = s.start
+	if s.peek() == '0' && strings.IndexByte("xXbB", s.peekNext()) >= 0 {
+		s.advance()
+		prefix := s.advance()
+		base, isBaseDigit := 16, isHexDigit
+		if prefix == 'b' || prefix == 'B' {
+			base, isBaseDigit = 2, isBinDigit
+		}
+		if !s.digits(isBaseDigit) {
+			s.malformed(fmt.Sprintf("expected digits after '0%c'", prefix))
+			return
+		}
+		if s.badSuffix() {
+			return
+		}
+		lit := strings.ReplaceAll(s.source[s.start+2:s.current], "_", "")
+		val, err := strconv.ParseInt(lit, base, 64)
+		if err != nil {
+			s.report(L_MALFORMED_NUMBER, "integer literal out of range")
+			return
+		}
+		s.literal(Number, val)
+		return
+	}
+
+	s.digits(isDigit)
+	isFloat := s.peek() == '.' || s.peek() == 'e' || s.peek() == 'E'
+	if s.peek() == '.' {
+		s.advance()
+		if !s.digits(isDigit) {
+			s.malformed("expected digits after '.'")
+			return
+		}
+	}
+	if s.peek() == 'e' || s.peek() == 'E' {
+		s.advance()
+		if s.peek() == '+' || s.peek() == '-' {
+			s.advance()
+		}
+		if !s.digits(isDigit) {
+			s.malformed("expected digits in exponent")
+			return
+		}
+	}
+	if s.badSuffix() {
+		return
+	}
+	lit := strings.ReplaceAll(s.source[s.start:s.current], "_", "")
+	if !isFloat {
+		val, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			s.report(L_MALFORMED_NUMBER, "integer literal out of range")
+			return
+		}
+		s.literal(Number, val)
+		return
+	}
+	val, err := strconv.ParseFloat(lit, 64)
+	if err != nil {
+		s.report(L_MALFORMED_NUMBER, "number literal out of range")
+		return
+	}
+	s.literal(Number, val)
+}
+
+// digits consumes a run of digits with single underscores between them ,
+// false if there is no digit
+func (s *Scanner) digits(isDigit func(byte) bool) bool {
+	if !isDigit(s.peek()) {
+		return false
+	}
+	for isDigit(s.peek()) || (s.peek() == '_' && isDigit(s.peekNext())) {
+		s.advance()
+	}
+	return true
+}
+
+// badSuffix reports letters , digits or underscores right after a number ,
+// like 0b102 , 1_ or 12px
+func (s *Scanner) badSuffix() bool {
+	if !isAlphaNum(s.peek()) {
+		return false
+	}
+	s.malformed(fmt.Sprintf("invalid character '%c' in number literal", s.peek()))
+	return true
+}
+
+// malformed reports the number being scanned , the rest of it is skipped so
+// that it turns into a single Illegal token
+func (s *Scanner) malformed(msg string) {
+	for isAlphaNum(s.peek()) {
+		s.advance()
+	}
+	s.report(L_MALFORMED_NUMBER, msg)
+}
+
+//match "* */" , important cases , atEnd , \n , not terminated
+func (s *Scanner) fullComment() {
+	for !(s.peek() == '*' && s.peekNext() == '/') && !s.atEnd() {
+		if s.advance() == '\n' {
+			s.newline()
+		}
+	}
+	if s.atEnd() {
+		s.report(L_UNTERMINATED_COMMENT, "unterminated /**/ comment")
+		return
+	}
+	s.advance()
+	s.advance()
+}
+
+func (s *Scanner) token(t token) {
+	s.literal(t, nil)
+}
+
+func (s *Scanner) literal(t token, literal interface{}) {
+	lex := s.source[s.start:s.current]
+	s.tokens = append(s.tokens, &tokenObj{
+		tok:     t,
+		lexeme:  lex,
+		literal: literal,
+		span:    s.span(),
+	})
+}
+
+// span is the span of the token being scanned , tokens that run over several
+// lines get the line and column they start on
+func (s *Scanner) span() Span {
+	return Span{
+		File:   s.file,
+		Start:  s.start,
+		End:    s.current,
+		Line:   s.startLine,
+		Column: utf8.RuneCountInString(s.source[s.startLineAt:s.start]) + 1,
+	}
+}
+
+// begin starts the next token at the current position
+func (s *Scanner) begin() {
+	s.start = s.current
+	s.startLine, s.startLineAt = s.line, s.lineAt
+}
+
+// newline is called after consuming a '\n'
+func (s *Scanner) newline() {
+	s.line++
+	s.lineAt = s.current
+}
+
+//parse string , escapes are decoded , """ starts a raw string
+func (s *Scanner) stringLit() {
+	if strings.HasPrefix(s.source[s.current:], `""`) {
+		s.current += 2
+		s.rawString()
+		return
+	}
+	s.stringBody()
+}
+
+// stringBody scans up to the closing quote , or up to ${ which ends the
+// segment with an Interpolation token , then the expression is scanned as
+// usual until its closing }
+//
+//	"a ${x} b" -> Interpolation("a ") Identifier(x) String(" b")
+func (s *Scanner) stringBody() {
+	b := &strings.Builder{}
+	for s.peek() != '"' && !s.atEnd() {
+		ch := s.advance()
+		switch {
+		case ch == '\\':
+			s.escape(b)
+		case ch == '$' && s.peek() == '{':
+			s.advance()
+			s.interps = append(s.interps, 0)
+			s.literal(Interpolation, b.String())
+			return
+		case ch == '\n':
+			s.newline()
+			b.WriteByte(ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	if s.atEnd() {
+		s.report(L_UNTERMINATED_STRING, "unterminated string")
+		return
+	}
+	s.advance()
+	//add string token , literal without two "
+	s.literal(String, b.String())
+}
+
+// escape decodes the escape sequence after a backslash into b
+//
+//	\n \t \r \0 \\ \" \$ \u{1F600}
+func (s *Scanner) escape(b *strings.Builder) {
+	start := s.current - 1
+	if s.atEnd() {
+		return // the caller reports the unterminated string
+	}
+	switch ch := s.advanceRune(); ch {
+	case 'n':
+		b.WriteByte('\n')
+	case 't':
+		b.WriteByte('\t')
+	case 'r':
+		b.WriteByte('\r')
+	case '0':
+		b.WriteByte(0)
+	case '\\', '"', '$':
+		b.WriteRune(ch)
+	case 'u':
+		if !s.match('{') {
+			s.errorAt(start, L_INVALID_ESCAPE, "expected '{' after \\u")
+			return
+		}
+		digits := s.current
+		for isHexDigit(s.peek()) {
+			s.advance()
+		}
+		hex := s.source[digits:s.current]
+		if !s.match('}') || hex == "" || len(hex) > 6 {
+			s.errorAt(start, L_INVALID_ESCAPE, "expected 1 to 6 hex digits in \\u{...}")
+			return
+		}
+		r, _ := strconv.ParseUint(hex, 16, 32)
+		if !utf8.ValidRune(rune(r)) {
+			s.errorAt(start, L_INVALID_ESCAPE, fmt.Sprintf("\\u{%v} is not a valid code point", hex))
+			return
+		}
+		b.WriteRune(rune(r))
+	default:
+		s.errorAt(start, L_INVALID_ESCAPE, fmt.Sprintf("invalid escape sequence '\\%c'", ch))
+		if ch == '\n' {
+			s.newline()
+		}
+	}
+}
+
+// rawString scans the rest of """...""" , nothing is escaped and it may run
+// over several lines , a newline right after the opening quotes is dropped
+func (s *Scanner) rawString() {
+	for !s.atEnd() && !strings.HasPrefix(s.source[s.current:], `"""`) {
+		if s.advance() == '\n' {
+			s.newline()
+		}
+	}
+	if s.atEnd() {
+		s.report(L_UNTERMINATED_STRING, "unterminated raw string")
+		return
+	}
+	lit := s.source[s.start+3 : s.current]
+	s.current += 3
+	if strings.HasPrefix(lit, "\r\n") {
+		lit = lit[2:]
+	} else if strings.HasPrefix(lit, "\n") {
+		lit = lit[1:]
+	}
+	s.literal(String, lit)
+}
+
+//scanner helpers
+func (s *Scanner) peekNext() byte {
+	if s.current+1 >= len(s.source) {
+		return byte(0)
+	}
+	return s.source[s.current+1]
+}
+
+// look ahead , if match then consume
+func (s *Scanner) match(ch byte) bool {
+	//if !s.atEnd() && s.source[s.current] == ch {
+	//	s.current++
+	//	return true
+	//}
+	//return false
+	if s.peek() != ch {
+		return false
+	}
+	s.current++
+	return true
+}
+
+func (s *Scanner) peek() byte {
+	if s.atEnd() {
+		return byte(0)
+	}
+	return s.source[s.current]
+}
+func (s *Scanner) atEnd() bool {
+	return s.current >= len(s.source)
+}
+
+//retrieve an token an advance
+func (s *Scanner) advance() byte {
+	i := s.current
+	s.current++
+	return s.source[i]
+}
+
+// advanceRune is advance for a whole UTF-8 sequence , invalid bytes come
+// back one at a time as utf8.RuneError
+func (s *Scanner) advanceRune() rune {
+	r, size := utf8.DecodeRuneInString(s.source[s.current:])
+	s.current += size
+	return r
+}
+
+// report records an error for the current token and emits it as Illegal
+func (s *Scanner) report(code, msg string) {
+	d := &Diagnostic{Severity: SEV_ERROR, Code: code, Message: msg, Span: s.span()}
+	s.errs = append(s.errs, d)
+	s.literal(Illegal, d)
+}
+
+// errorAt records an error for source[start:current] on the current line ,
+// like a bad escape , the token being scanned goes on
+func (s *Scanner) errorAt(start int, code, msg string) {
+	span := Span{
+		File:   s.file,
+		Start:  start,
+		End:    s.current,
+		Line:   s.line,
+		Column: utf8.RuneCountInString(s.source[s.lineAt:start]) + 1,
+	}
+	s.errs = append(s.errs, &Diagnostic{Severity: SEV_ERROR, Code: code, Message: msg, Span: span})
+}
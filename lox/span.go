@@ -0,0 +1,88 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ------------------------------------------
+// source positions and the caret underlined error display
+
+// Span is the part of a source file a token or node was read from.
+// Start and End are byte offsets , Line and Column count from 1 , Column is
+// in runes. Column is 0 when only the line is known , like for errors raised
+// by the VM from its line table.
+type Span struct {
+	File   string `json:"file,omitempty"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (s Span) String() string {
+	pos := fmt.Sprintf("%v", s.Line)
+	if s.Column > 0 {
+		pos = fmt.Sprintf("%v:%v", s.Line, s.Column)
+	}
+	if s.File == "" {
+		return pos
+	}
+	return s.File + ":" + pos
+}
+
+// to is the span from the start of s to the end of o
+func (s Span) to(o Span) Span {
+	if o.End > s.End {
+		s.End = o.End
+	}
+	return s
+}
+
+// underline quotes the line of source s starts on , with ^~~~ under the
+// span. multi-line spans are underlined to the end of their first line.
+func (s Span) underline(source string) string {
+	lines := strings.Split(source, "\n")
+	if s.Line < 1 || s.Line > len(lines) {
+		return ""
+	}
+	text := strings.TrimRight(lines[s.Line-1], "\r")
+	num := fmt.Sprintf("%v", s.Line)
+	gutter := strings.Repeat(" ", len(num))
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%v--> %v\n", gutter, s)
+	fmt.Fprintf(b, "%v |\n", gutter)
+	fmt.Fprintf(b, "%v | %v", num, text)
+	if s.Column == 0 {
+		return b.String()
+	}
+
+	// pad with the same tabs as the line so that the caret lines up
+	pad := &strings.Builder{}
+	col := 1
+	for _, r := range text {
+		if col == s.Column {
+			break
+		}
+		if r == '\t' {
+			pad.WriteRune('\t')
+		} else {
+			pad.WriteRune(' ')
+		}
+		col++
+	}
+	width := 1
+	if s.End > s.Start && s.End <= len(source) {
+		lexeme := source[s.Start:s.End]
+		if i := strings.IndexByte(lexeme, '\n'); i >= 0 {
+			lexeme = lexeme[:i]
+		}
+		if n := utf8.RuneCountInString(lexeme); n > 1 {
+			width = n
+		}
+	}
+	fmt.Fprintf(b, "\n%v | %v^%v", gutter, pad, strings.Repeat("~", width-1))
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package lox
+
+type (
+	Stmt interface {
+		aStmt()
+		execute(*Env) Completion
+		accept(*Resolver)
+	}
+
+	stmt struct{}
+
+	BlockStmt struct {
+		list []Stmt
+		stmt
+	}
+
+	BreakStmt struct {
+		keyword *tokenObj
+		stmt
+	}
+
+	ContinueStmt struct {
+		keyword *tokenObj
+		stmt
+	}
+
+	ExprStmt struct {
+		expression Expr
+		stmt
+	}
+
+	FunStmt struct { //declaration of a function
+		name      *tokenObj
+		params    []*tokenObj
+		body      []Stmt
+		generator bool // contains a yield , set by the resolver
+		stmt           // something like extend
+	}
+
+	// import "lib.glx" as lib; binds the module , from "lib.glx" import a as b;
+	// binds its exports
+	ImportStmt struct {
+		keyword *tokenObj
+		path    *tokenObj // the string literal
+		name    *tokenObj // import ... as name , nil for from
+		exports []*tokenObj
+		aliases []*tokenObj // the names exports are bound to
+		stmt
+	}
+
+	// if structure
+	IfStmt struct {
+		condition      Expr
+		block1, block2 Stmt
+		stmt
+	}
+
+	PrintStmt struct {
+		expression Expr
+		stmt
+	}
+
+	ReturnStmt struct {
+		keyword *tokenObj
+		value   Expr
+		stmt
+	}
+
+	ThrowStmt struct {
+		keyword *tokenObj
+		value   Expr
+		stmt
+	}
+
+	// try with a catch , a finally or both
+	TryStmt struct {
+		keyword *tokenObj
+		body    []Stmt
+		name    *tokenObj // the catch variable , nil without a catch
+		catch   []Stmt
+		finally []Stmt // nil without a finally
+		stmt
+	}
+
+	YieldStmt struct {
+		keyword *tokenObj
+		value   Expr // nil for yield;
+		stmt
+	}
+
+	VarStmt struct {
+		name *tokenObj
+		init Expr
+		stmt
+	}
+
+	WhileStmt struct {
+		keyword   *tokenObj // while or for , for errors raised by the limits
+		condition Expr
+		body      Stmt
+		increment Expr // for loop increment , still runs after continue
+		stmt
+	}
+	// for (name in iterable) or for (key, name in iterable)
+	ForInStmt struct {
+		keyword  *tokenObj
+		key      *tokenObj // nil with one variable
+		name     *tokenObj
+		iterable Expr
+		body     Stmt
+		stmt
+	}
+	ClassStmt struct {
+		name       *tokenObj
+		methods    []*FunStmt
+		superClass *VarExpr //todo
+
+		stmt
+	}
+)
+
+func (*stmt) aStmt()                    {}
+func (*stmt) accept(resolver *Resolver) {}
+func (*stmt) execute(*Env) Completion   { return Completion{} }
+
+func (s *VarStmt) accept(r *Resolver) {
+	r.visitVarStmt(s)
+}
+
+func (s *WhileStmt) accept(r *Resolver) {
+	r.visitWhileStmt(s)
+}
+func (s *ForInStmt) accept(r *Resolver) {
+	r.visitForInStmt(s)
+}
+func (s *ReturnStmt) accept(r *Resolver) {
+	r.visitReturnStmt(s)
+}
+
+func (s *ThrowStmt) accept(r *Resolver) {
+	r.visitThrowStmt(s)
+}
+
+func (s *TryStmt) accept(r *Resolver) {
+	r.visitTryStmt(s)
+}
+
+func (s *YieldStmt) accept(r *Resolver) {
+	r.visitYieldStmt(s)
+}
+
+func (s *PrintStmt) accept(r *Resolver) {
+	r.visitPrintStmt(s)
+}
+func (s *ImportStmt) accept(r *Resolver) {
+	r.visitImportStmt(s)
+}
+
+func (s *IfStmt) accept(r *Resolver) {
+	r.visitIfStmt(s)
+}
+
+func (s *FunStmt) accept(r *Resolver) {
+	r.visitFunctionStmt(s)
+}
+func (s *ExprStmt) accept(r *Resolver) {
+	r.visitExpressionStmt(s)
+}
+func (s *ClassStmt) accept(r *Resolver) {
+	r.visitClassStmt(s)
+}
+func (s *ContinueStmt) accept(r *Resolver) {
+	r.visitContinueStmt(s)
+}
+func (s *BreakStmt) accept(r *Resolver) {
+	r.visitBreakStmt(s)
+}
+func (s *BlockStmt) accept(r *Resolver) {
+	r.visitBlockStmt(s)
+}
@@ -1,4 +1,4 @@
-package main
+package lox
 
 import "fmt"
 
@@ -10,22 +10,30 @@ type token uint
 var keywords = map[string]token{
 	"and":      And,
 	"break":    Break,
+	"catch":    Catch,
 	"class":    Class,
 	"continue": Continue,
+	"div":      Div,
 	"else":     Else,
 	"false":    False,
+	"finally":  Finally,
 	"for":      For,
+	"from":     From,
 	"fun":      Fun,
 	"if":       If,
+	"import":   Import,
 	"nil":      Nil,
 	"or":       Or,
 	"print":    Print,
 	"return":   Return,
 	"super":    Super,
 	"this":     This,
+	"throw":    Throw,
 	"true":     True,
+	"try":      Try,
 	"var":      Var,
 	"while":    While,
+	"yield":    Yield,
 }
 
 const (
@@ -34,15 +42,30 @@ const (
 	RightParen
 	LeftBrace
 	RightBrace
+	LeftBracket  // [
+	RightBracket // ]
 	Comma
 	Dot
 	Minus
+	MinusMinus // --
+	MinusEqual // -=
 	Plus
+	PlusPlus  // ++
+	PlusEqual // +=
 	Semicolon
 	Colon
 	Question
 	Slash
+	SlashEqual // /=
 	Star
+	StarStar     // **
+	StarEqual    // *=
+	Percent      // %
+	PercentEqual // %=
+	Amp          // &
+	Pipe         // |
+	Caret        // ^
+	Tilde        // ~
 
 	Bang
 	BangEqual
@@ -52,39 +75,53 @@ const (
 	GreaterEqual
 	Less
 	LessEqual
+	LessLess       // <<
+	GreaterGreater // >>
 
 	Identifier
 	String
+	// a string segment followed by ${
+	Interpolation //interpolation
 	Number
 
 	And
 	Break
+	Catch //catch
 	Class
 	Continue
+	Div //div
 	Else
 	False
+	Finally //finally
 	Fun
 	For
+	From //from
 	If
+	Import //import
 	Nil
 	Or
 	Print
 	Return
 	Super
 	This
+	Throw //throw
 	True  //true
+	Try   //try
 	Var   //var
 	While //while
+	Yield //yield
 
-	EOF //eof
+	// literal is the scanner's diagnostic
+	Illegal //illegal
+	EOF     //eof
 
 )
 
 type tokenObj struct {
 	tok     token
 	lexeme  string
-	line    int
 	literal interface{}
+	span    Span
 }
 
 func (t *tokenObj) String() string {
@@ -0,0 +1,751 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ------------------------------------------
+// stack based virtual machine , the second execution backend
+//
+// interpret stays the reference implementation , run a script with
+// golox -vm to execute the same AST through compile and the VM instead
+
+// vmFunction is the compiled form of FunStmt , FunExpr or the whole script
+type vmFunction struct {
+	name         string
+	params       []string
+	arity        int
+	upvalueCount int
+	chunk        Chunk
+	generator    bool // has a yield , a call makes a LoxGenerator
+}
+
+func (f *vmFunction) String() string {
+	return stringify(f)
+}
+
+type vmClosure struct {
+	fn       *vmFunction
+	upvalues []*vmUpvalue
+	globals  map[string]value // of the module the closure was made in
+}
+
+func (c *vmClosure) String() string {
+	return stringify(c)
+}
+
+// vmUpvalue points into the stack while the captured local is alive ,
+// then owns the value once the local goes out of scope
+type vmUpvalue struct {
+	slot   int
+	open   bool
+	closed value
+	next   *vmUpvalue // open upvalues , sorted by slot from the top of the stack
+	owner  *VM        // whose stack slot is in , a generator has its own
+}
+
+type vmClass struct {
+	name    string
+	methods map[string]*vmClosure
+}
+
+func (c *vmClass) String() string {
+	return stringify(c)
+}
+
+type vmInstance struct {
+	klass  *vmClass
+	fields map[string]value
+}
+
+func (i *vmInstance) String() string {
+	return stringify(i)
+}
+
+type vmBoundMethod struct {
+	receiver value
+	method   *vmClosure
+}
+
+func (b *vmBoundMethod) String() string {
+	return stringify(b)
+}
+
+// vmIter is the hidden local of a for-in loop
+type vmIter struct {
+	next iterator
+	pair bool
+}
+
+// uninitialized is the value of var a; until something is assigned
+type uninitialized struct {
+	name string
+}
+
+type callFrame struct {
+	closure *vmClosure
+	ip      int
+	base    int // stack index of slot 0
+}
+
+// vmHandler is a try in progress , an error unwinds the stack to it
+type vmHandler struct {
+	frames int  // len(vm.frames) at OP_TRY
+	stack  int  // len(vm.stack) at OP_TRY
+	ip     int  // start of the catch or finally code
+	catch  bool // OP_TRY , the code gets the caught value instead of the error
+}
+
+type VM struct {
+	stack        []value
+	frames       []callFrame
+	handlers     []vmHandler
+	globals      map[string]value
+	openUpvalues *vmUpvalue
+	stdout       io.Writer
+	limits       *limits
+	interp       *Interpreter // owner , for imports
+	depthBase    int          // frames of the VMs resuming this generator VM
+}
+
+func NewVM(stdout io.Writer) *VM {
+	return &VM{
+		stack:   make([]value, 0, 256),
+		frames:  make([]callFrame, 0, 64),
+		globals: make(map[string]value),
+		stdout:  stdout,
+		limits:  newLimits(Options{}),
+	}
+}
+
+// interpret runs the compiled script and returns what the script returned ,
+// runtime errors come back as RuntimeError
+func (vm *VM) interpret(script *vmFunction) (value, error) {
+	return vm.callFunction(&vmClosure{fn: script, globals: vm.globals}, nil)
+}
+
+// runModule runs the script of an imported module in its own globals ,
+// nested in the run of the import
+func (vm *VM) runModule(script *vmFunction, globals map[string]value) {
+	vm.invoke(nil, &vmClosure{fn: script, globals: globals})
+}
+
+// invoke calls fn without arguments and runs it to the end , for Go code
+// running inside dispatch , like an iterator calling next()
+func (vm *VM) invoke(_ *tokenObj, fn value) value {
+	depth := len(vm.frames)
+	vm.push(fn)
+	vm.callValue(fn, 0)
+	if len(vm.frames) > depth {
+		vm.run(depth)
+	}
+	return vm.pop()
+}
+
+// method is the iterHost lookup of a method , fields first like
+// OP_GET_PROPERTY
+func (vm *VM) method(v value, name string) (value, bool) {
+	o, ok := v.(*vmInstance)
+	if !ok {
+		return nil, false
+	}
+	if f, ok := o.fields[name]; ok {
+		return f, true
+	}
+	if m, ok := o.klass.methods[name]; ok {
+		return &vmBoundMethod{receiver: o, method: m}, true
+	}
+	return nil, false
+}
+
+// callFunction calls fn with args and runs until it returns
+func (vm *VM) callFunction(fn value, args []value) (v value, err error) {
+	// a Go function may call back in while the VM runs , an error only
+	// unwinds what this call pushed
+	depth, base, handlers := len(vm.frames), len(vm.stack), len(vm.handlers)
+	defer func() {
+		if e := recover(); e != nil {
+			re, ok := e.(RuntimeError)
+			if !ok {
+				panic(e)
+			}
+			err = re
+			vm.closeUpvalues(base)
+			vm.stack = vm.stack[:base]
+			vm.frames = vm.frames[:depth]
+			vm.handlers = vm.handlers[:handlers]
+		}
+	}()
+	vm.push(fn)
+	for _, a := range args {
+		vm.push(a)
+	}
+	vm.callValue(fn, len(args))
+	if len(vm.frames) > depth {
+		vm.run(depth)
+	}
+	return vm.pop(), nil
+}
+
+// ------------------------------------------
+// stack helpers
+
+func (vm *VM) push(v value) {
+	vm.stack = append(vm.stack, v)
+}
+
+func (vm *VM) pop() value {
+	v := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return v
+}
+
+func (vm *VM) peek(distance int) value {
+	return vm.stack[len(vm.stack)-1-distance]
+}
+
+// token makes a token at the current position , so that errors raised by the
+// shared runtime types (lists, maps) report the right position
+func (vm *VM) token(lexeme string) *tokenObj {
+	frame := &vm.frames[len(vm.frames)-1]
+	return &tokenObj{lexeme: lexeme, span: frame.closure.fn.chunk.spans[frame.ip-1]}
+}
+
+func (vm *VM) runtimeError(format string, args ...interface{}) {
+	if len(vm.frames) == 0 {
+		panic(newRuntimeError(Span{}, fmt.Sprintf(format, args...))) // called from Go , no line
+	}
+	runtimeErr(vm.token(""), fmt.Sprintf(format, args...))
+}
+
+// limitError is runtimeError for the limits , try can't catch it
+func (vm *VM) limitError(msg string) {
+	var span Span
+	if len(vm.frames) > 0 {
+		span = vm.token("").span
+	}
+	err := newRuntimeError(span, msg)
+	err.fatal = true
+	panic(err)
+}
+
+// ------------------------------------------
+// calls
+
+func (vm *VM) callValue(callee value, argc int) {
+	switch c := callee.(type) {
+	case *vmClosure:
+		vm.call(c, argc)
+		return
+	case *vmBoundMethod:
+		vm.stack[len(vm.stack)-argc-1] = c.receiver
+		vm.call(c.method, argc)
+		return
+	case *vmClass:
+		vm.stack[len(vm.stack)-argc-1] = &vmInstance{klass: c, fields: make(map[string]value)}
+		if init, ok := c.methods["init"]; ok {
+			vm.call(init, argc)
+		} else if argc != 0 {
+			vm.runtimeError("expected 0 arguments but got %v", argc)
+		}
+		return
+	case *goFunc:
+		args := make([]value, argc)
+		copy(args, vm.stack[len(vm.stack)-argc:])
+		var paren *tokenObj
+		if len(vm.frames) > 0 {
+			paren = vm.token(")")
+		}
+		result := c.callAt(paren, args)
+		vm.stack = vm.stack[:len(vm.stack)-argc-1]
+		vm.push(result)
+		return
+	case Callable: // natives shared with the tree-walker
+		if argc != c.arity() {
+			vm.runtimeError("expected %v arguments but got %v", c.arity(), argc)
+		}
+		args := make([]value, argc)
+		copy(args, vm.stack[len(vm.stack)-argc:])
+		result := c.call(nil, args)
+		vm.stack = vm.stack[:len(vm.stack)-argc-1]
+		vm.push(result)
+		return
+	}
+	vm.runtimeError("'%v' is not a function or class", stringify(callee))
+}
+
+func (vm *VM) call(closure *vmClosure, argc int) {
+	if argc != closure.fn.arity {
+		vm.runtimeError("expected %v arguments but got %v", closure.fn.arity, argc)
+	}
+	if msg := vm.limits.step(); msg != "" {
+		vm.limitError(msg)
+	}
+	if vm.depthBase+len(vm.frames) > vm.limits.maxDepth { // the script or Go call has frame 0
+		vm.runtimeError("stack overflow")
+	}
+	if closure.fn.generator {
+		base := len(vm.stack) - argc - 1
+		g := vm.newGenerator(closure, vm.stack[base:])
+		vm.stack = vm.stack[:base]
+		vm.push(g)
+		return
+	}
+	vm.frames = append(vm.frames, callFrame{
+		closure: closure,
+		base:    len(vm.stack) - argc - 1,
+	})
+}
+
+func (vm *VM) bindMethod(klass *vmClass, name string) {
+	method, ok := klass.methods[name]
+	if !ok {
+		vm.runtimeError("Undefined property '%v'.", name)
+	}
+	vm.stack[len(vm.stack)-1] = &vmBoundMethod{receiver: vm.peek(0), method: method}
+}
+
+func (vm *VM) captureUpvalue(slot int) *vmUpvalue {
+	var prev *vmUpvalue
+	up := vm.openUpvalues
+	for up != nil && up.slot > slot {
+		prev = up
+		up = up.next
+	}
+	if up != nil && up.slot == slot {
+		return up
+	}
+	created := &vmUpvalue{slot: slot, open: true, next: up, owner: vm}
+	if prev == nil {
+		vm.openUpvalues = created
+	} else {
+		prev.next = created
+	}
+	return created
+}
+
+// closeUpvalues moves every captured local at or above slot off the stack
+func (vm *VM) closeUpvalues(slot int) {
+	for vm.openUpvalues != nil && vm.openUpvalues.slot >= slot {
+		up := vm.openUpvalues
+		up.closed = vm.stack[up.slot]
+		up.open = false
+		vm.openUpvalues = up.next
+	}
+}
+
+func (vm *VM) upvalueGet(up *vmUpvalue) value {
+	if up.open {
+		return up.owner.stack[up.slot]
+	}
+	return up.closed
+}
+
+func (vm *VM) upvalueSet(up *vmUpvalue, v value) {
+	if up.open {
+		up.owner.stack[up.slot] = v
+	} else {
+		up.closed = v
+	}
+}
+
+// checkInit rejects reads of a variable declared without an initializer
+func (vm *VM) checkInit(v value) value {
+	if u, ok := v.(uninitialized); ok {
+		vm.runtimeError("variable '%v' should be initialized first", u.name)
+	}
+	return v
+}
+
+// ------------------------------------------
+// dispatch loop
+
+// run executes until the frame count drops back to depth , an error raised
+// under a handler of this run unwinds to the handler and dispatch goes on.
+// a generator VM is resumed with the handlers of its frame still there
+func (vm *VM) run(depth int) {
+	base := len(vm.handlers)
+	for base > 0 && vm.handlers[base-1].frames > depth {
+		base--
+	}
+	for vm.dispatchCatching(depth, base) {
+	}
+}
+
+// dispatchCatching reports whether dispatch stopped on an error it caught
+func (vm *VM) dispatchCatching(depth, base int) (caught bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(RuntimeError)
+			if !ok || err.fatal || len(vm.handlers) == base {
+				panic(e)
+			}
+			vm.unwind(err)
+			caught = true
+		}
+	}()
+	vm.dispatch(depth)
+	return false
+}
+
+// unwind drops the frames and values above the innermost handler and pushes
+// what its code expects , the caught value or the pending error
+func (vm *VM) unwind(err RuntimeError) {
+	h := vm.handlers[len(vm.handlers)-1]
+	vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	vm.closeUpvalues(h.stack)
+	vm.frames = vm.frames[:h.frames]
+	vm.stack = vm.stack[:h.stack]
+	vm.frames[h.frames-1].ip = h.ip
+	if h.catch {
+		v, _ := catchValue(err)
+		vm.push(v)
+	} else {
+		vm.push(err)
+	}
+}
+
+func (vm *VM) dispatch(depth int) {
+	frame := &vm.frames[len(vm.frames)-1]
+	chunk := &frame.closure.fn.chunk
+
+	readByte := func() byte {
+		b := chunk.code[frame.ip]
+		frame.ip++
+		return b
+	}
+	readShort := func() int {
+		n := chunk.readShort(frame.ip)
+		frame.ip += 2
+		return n
+	}
+	readString := func() string {
+		return chunk.constants[readShort()].(string)
+	}
+	// frames may be appended or dropped by calls and returns
+	reload := func() {
+		frame = &vm.frames[len(vm.frames)-1]
+		chunk = &frame.closure.fn.chunk
+	}
+
+	for {
+		switch opcode(readByte()) {
+		case OP_CONSTANT:
+			vm.push(chunk.constants[readShort()])
+		case OP_NIL:
+			vm.push(nil)
+		case OP_TRUE:
+			vm.push(true)
+		case OP_FALSE:
+			vm.push(false)
+		case OP_UNINIT:
+			vm.push(uninitialized{name: readString()})
+		case OP_POP:
+			vm.pop()
+		case OP_GET_LOCAL:
+			vm.push(vm.checkInit(vm.stack[frame.base+int(readByte())]))
+		case OP_SET_LOCAL:
+			vm.stack[frame.base+int(readByte())] = vm.peek(0)
+		case OP_GET_GLOBAL:
+			name := readString()
+			v, ok := frame.closure.globals[name]
+			if !ok {
+				vm.runtimeError("undefined variable '%v'", name)
+			}
+			vm.push(vm.checkInit(v))
+		case OP_DEFINE_GLOBAL:
+			frame.closure.globals[readString()] = vm.pop()
+		case OP_SET_GLOBAL:
+			name := readString()
+			if _, ok := frame.closure.globals[name]; !ok {
+				vm.runtimeError("undefined variable '%v'", name)
+			}
+			frame.closure.globals[name] = vm.peek(0)
+		case OP_GET_UPVALUE:
+			vm.push(vm.checkInit(vm.upvalueGet(frame.closure.upvalues[readByte()])))
+		case OP_SET_UPVALUE:
+			vm.upvalueSet(frame.closure.upvalues[readByte()], vm.peek(0))
+		case OP_GET_PROPERTY:
+			name := readString()
+			switch o := vm.peek(0).(type) {
+			case *vmInstance:
+				if v, ok := o.fields[name]; ok {
+					vm.stack[len(vm.stack)-1] = v
+				} else {
+					vm.bindMethod(o.klass, name)
+				}
+			case *LoxList:
+				vm.stack[len(vm.stack)-1] = o.get(vm.token(name))
+			case *LoxMap:
+				vm.stack[len(vm.stack)-1] = o.get(vm.token(name))
+			case string:
+				vm.stack[len(vm.stack)-1] = stringGet(o, vm.token(name))
+			case *LoxError:
+				vm.stack[len(vm.stack)-1] = o.get(vm.token(name))
+			case *LoxModule:
+				vm.stack[len(vm.stack)-1] = o.get(vm.token(name))
+			case *LoxGenerator:
+				vm.stack[len(vm.stack)-1] = o.get(vm.token(name))
+			default:
+				vm.runtimeError("Only instance have properties")
+			}
+		case OP_SET_PROPERTY:
+			name := readString()
+			o, ok := vm.peek(1).(*vmInstance)
+			if !ok {
+				vm.runtimeError("Only instances have fields.")
+			}
+			v := vm.pop()
+			o.fields[name] = v
+			vm.stack[len(vm.stack)-1] = v
+		case OP_GET_SUPER:
+			name := readString()
+			superClass := vm.pop().(*vmClass)
+			vm.bindMethod(superClass, name)
+		case OP_INDEX_GET:
+			index := vm.pop()
+			switch o := vm.peek(0).(type) {
+			case *LoxList:
+				vm.stack[len(vm.stack)-1] = o.getAt(vm.token("["), index)
+			case *LoxMap:
+				vm.stack[len(vm.stack)-1] = o.getAt(vm.token("["), index)
+			case string:
+				vm.stack[len(vm.stack)-1] = stringAt(vm.token("["), o, index)
+			default:
+				vm.runtimeError("Only lists, maps and strings can be indexed.")
+			}
+		case OP_INDEX_SET:
+			v := vm.pop()
+			index := vm.pop()
+			switch o := vm.peek(0).(type) {
+			case *LoxList:
+				o.setAt(vm.token("["), index, v)
+			case *LoxMap:
+				o.setAt(vm.token("["), index, v)
+			default:
+				vm.runtimeError("Only lists and maps can be indexed.")
+			}
+			vm.stack[len(vm.stack)-1] = v
+		case OP_EQUAL:
+			y := vm.pop()
+			vm.stack[len(vm.stack)-1] = valuesEqual(vm.peek(0), y)
+		case OP_NOT_EQUAL:
+			y := vm.pop()
+			vm.stack[len(vm.stack)-1] = !valuesEqual(vm.peek(0), y)
+		case OP_GREATER:
+			vm.arith(Greater)
+		case OP_GREATER_EQUAL:
+			vm.arith(GreaterEqual)
+		case OP_LESS:
+			vm.arith(Less)
+		case OP_LESS_EQUAL:
+			vm.arith(LessEqual)
+		case OP_ADD:
+			vm.arith(Plus)
+		case OP_SUBTRACT:
+			vm.arith(Minus)
+		case OP_MULTIPLY:
+			vm.arith(Star)
+		case OP_DIVIDE:
+			vm.arith(Slash)
+		case OP_FLOOR_DIVIDE:
+			vm.arith(Div)
+		case OP_MODULO:
+			vm.arith(Percent)
+		case OP_POWER:
+			vm.arith(StarStar)
+		case OP_BIT_AND:
+			vm.arith(Amp)
+		case OP_BIT_OR:
+			vm.arith(Pipe)
+		case OP_BIT_XOR:
+			vm.arith(Caret)
+		case OP_SHIFT_LEFT:
+			vm.arith(LessLess)
+		case OP_SHIFT_RIGHT:
+			vm.arith(GreaterGreater)
+		case OP_BIT_NOT:
+			v, msg := complement(vm.peek(0))
+			if msg != "" {
+				vm.runtimeError("%v", msg)
+			}
+			vm.stack[len(vm.stack)-1] = v
+		case OP_NOT:
+			vm.stack[len(vm.stack)-1] = !isTruthy(vm.peek(0))
+		case OP_NEGATE:
+			v, msg := negate(vm.peek(0))
+			if msg != "" {
+				vm.runtimeError("%v", msg)
+			}
+			vm.stack[len(vm.stack)-1] = v
+		case OP_PRINT:
+			fmt.Fprintln(vm.stdout, stringify(vm.pop()))
+		case OP_JUMP:
+			offset := readShort()
+			frame.ip += offset
+		case OP_JUMP_IF_FALSE:
+			offset := readShort()
+			if !isTruthy(vm.peek(0)) {
+				frame.ip += offset
+			}
+		case OP_LOOP:
+			offset := readShort()
+			if msg := vm.limits.step(); msg != "" {
+				vm.limitError(msg)
+			}
+			frame.ip -= offset
+		case OP_CALL:
+			argc := int(readByte())
+			vm.callValue(vm.peek(argc), argc)
+			reload()
+		case OP_CLOSURE:
+			fn := chunk.constants[readShort()].(*vmFunction)
+			closure := &vmClosure{fn: fn, upvalues: make([]*vmUpvalue, fn.upvalueCount), globals: frame.closure.globals}
+			for i := range closure.upvalues {
+				isLocal := readByte()
+				index := int(readByte())
+				if isLocal == 1 {
+					closure.upvalues[i] = vm.captureUpvalue(frame.base + index)
+				} else {
+					closure.upvalues[i] = frame.closure.upvalues[index]
+				}
+			}
+			vm.push(closure)
+		case OP_CLOSE_UPVALUE:
+			vm.closeUpvalues(len(vm.stack) - 1)
+			vm.pop()
+		case OP_RETURN:
+			result := vm.pop()
+			vm.closeUpvalues(frame.base)
+			vm.frames = vm.frames[:len(vm.frames)-1]
+			vm.stack = vm.stack[:frame.base]
+			vm.push(result)
+			if len(vm.frames) == depth {
+				return
+			}
+			reload()
+		case OP_INTERPOLATE:
+			n := readShort()
+			b := &strings.Builder{}
+			for _, part := range vm.stack[len(vm.stack)-n:] {
+				b.WriteString(stringify(part))
+			}
+			vm.stack = vm.stack[:len(vm.stack)-n]
+			vm.push(b.String())
+		case OP_DUP:
+			n := int(readByte())
+			vm.stack = append(vm.stack, vm.stack[len(vm.stack)-n:]...)
+		case OP_ROTATE:
+			n := int(readByte())
+			top := len(vm.stack) - 1
+			v := vm.stack[top]
+			copy(vm.stack[top-n+1:], vm.stack[top-n:top])
+			vm.stack[top-n] = v
+		case OP_COMPOUND:
+			op := token(readByte())
+			var y value
+			if op != PlusPlus && op != MinusMinus {
+				y = vm.pop()
+			}
+			v, msg := compound(op, vm.peek(0), y)
+			if msg != "" {
+				vm.runtimeError("%v", msg)
+			}
+			vm.stack[len(vm.stack)-1] = v
+		case OP_IMPORT:
+			path := readString()
+			vm.push(vm.interp.importModule(vm.token(path), path))
+			reload() // the module ran in frames that may have moved vm.frames
+		case OP_ITER:
+			pair := readByte() == 1
+			it := newIterator(vm.token("for"), vm, vm.pop(), pair)
+			vm.push(&vmIter{next: it, pair: pair})
+			reload() // iter() may have run
+		case OP_YIELD:
+			return // the value stays on top , resume pops it
+		case OP_FOR_NEXT:
+			offset := readShort()
+			it := vm.peek(0).(*vmIter)
+			k, v, ok := it.next()
+			reload() // so may next()
+			if !ok {
+				frame.ip += offset
+				break
+			}
+			if it.pair {
+				vm.push(k)
+			}
+			vm.push(v)
+		case OP_TRY, OP_TRY_FINALLY:
+			op := opcode(chunk.code[frame.ip-1])
+			offset := readShort()
+			vm.handlers = append(vm.handlers, vmHandler{
+				frames: len(vm.frames),
+				stack:  len(vm.stack),
+				ip:     frame.ip + offset,
+				catch:  op == OP_TRY,
+			})
+		case OP_END_TRY:
+			vm.handlers = vm.handlers[:len(vm.handlers)-1]
+		case OP_THROW:
+			panic(throwError(vm.token("").span, vm.pop()))
+		case OP_RETHROW:
+			panic(vm.pop().(RuntimeError))
+		case OP_LIST:
+			n := readShort()
+			elements := make([]value, n)
+			copy(elements, vm.stack[len(vm.stack)-n:])
+			vm.stack = vm.stack[:len(vm.stack)-n]
+			vm.push(&LoxList{elements: elements})
+		case OP_MAP:
+			n := readShort()
+			m := NewLoxMap()
+			entries := vm.stack[len(vm.stack)-2*n:]
+			for i := 0; i < n; i++ {
+				m.setAt(vm.token("{"), entries[2*i], entries[2*i+1])
+			}
+			vm.stack = vm.stack[:len(vm.stack)-2*n]
+			vm.push(m)
+		case OP_CLASS:
+			vm.push(&vmClass{name: readString(), methods: make(map[string]*vmClosure)})
+		case OP_INHERIT:
+			superClass, ok := vm.peek(1).(*vmClass)
+			if !ok {
+				vm.runtimeError("Superclass must be a class.")
+			}
+			subClass := vm.peek(0).(*vmClass)
+			// copy-down inheritance , methods are fixed once the class is built
+			for name, m := range superClass.methods {
+				subClass.methods[name] = m
+			}
+			vm.pop()
+		case OP_METHOD:
+			name := readString()
+			method := vm.peek(0).(*vmClosure)
+			vm.peek(1).(*vmClass).methods[name] = method
+			vm.pop()
+		default:
+			vm.runtimeError("unknown opcode %v", chunk.code[frame.ip-1])
+		}
+	}
+}
+
+// arith pops two operands and pushes op applied to them , like
+// BinaryExpr.eval
+func (vm *VM) arith(op token) {
+	y := vm.pop()
+	x := vm.pop()
+	var v value
+	var msg string
+	if op == Plus {
+		v, msg = add(x, y)
+	} else {
+		v, msg = arith(op, x, y)
+	}
+	if msg != "" {
+		vm.runtimeError("%v", msg)
+	}
+	vm.push(v)
+}
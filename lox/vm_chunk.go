@@ -0,0 +1,262 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ------------------------------------------
+// bytecode
+//
+// every instruction is one opcode byte followed by its operands ,
+// constant and jump operands are 2 bytes big-endian , slots and counts 1 byte
+
+type opcode byte
+
+const (
+	OP_CONSTANT opcode = iota // u16 constant index
+	OP_NIL
+	OP_TRUE
+	OP_FALSE
+	OP_UNINIT // u16 name , placeholder for var a;
+	OP_POP
+	OP_GET_LOCAL     // u8 slot
+	OP_SET_LOCAL     // u8 slot
+	OP_GET_GLOBAL    // u16 name
+	OP_DEFINE_GLOBAL // u16 name
+	OP_SET_GLOBAL    // u16 name
+	OP_GET_UPVALUE   // u8 index
+	OP_SET_UPVALUE   // u8 index
+	OP_GET_PROPERTY  // u16 name
+	OP_SET_PROPERTY  // u16 name
+	OP_GET_SUPER     // u16 name
+	OP_INDEX_GET
+	OP_INDEX_SET
+	OP_EQUAL
+	OP_NOT_EQUAL
+	OP_GREATER
+	OP_GREATER_EQUAL
+	OP_LESS
+	OP_LESS_EQUAL
+	OP_ADD
+	OP_SUBTRACT
+	OP_MULTIPLY
+	OP_DIVIDE
+	OP_FLOOR_DIVIDE
+	OP_MODULO
+	OP_POWER
+	OP_BIT_AND
+	OP_BIT_OR
+	OP_BIT_XOR
+	OP_SHIFT_LEFT
+	OP_SHIFT_RIGHT
+	OP_BIT_NOT
+	OP_NOT
+	OP_NEGATE
+	OP_PRINT
+	OP_JUMP          // u16 forward offset
+	OP_JUMP_IF_FALSE // u16 forward offset , keeps the condition on stack
+	OP_LOOP          // u16 backward offset
+	OP_CALL          // u8 argument count
+	OP_CLOSURE       // u16 function , then (isLocal u8 , index u8) per upvalue
+	OP_CLOSE_UPVALUE
+	OP_RETURN
+	OP_LIST  // u16 element count
+	OP_MAP   // u16 entry count
+	OP_CLASS // u16 name
+	OP_INHERIT
+	OP_METHOD      // u16 name
+	OP_INTERPOLATE // u16 part count , joins the parts into one string
+	OP_DUP         // u8 count , pushes copies of the top count values
+	OP_ROTATE      // u8 depth , moves the top value below the depth values under it
+	OP_COMPOUND    // u8 token , x op= y or x++ , see compound
+	OP_TRY         // u16 forward offset to the catch , which gets the caught value
+	OP_TRY_FINALLY // u16 forward offset to the finally , which gets the pending error
+	OP_END_TRY
+	OP_THROW
+	OP_RETHROW  // raises the pending error again
+	OP_IMPORT   // u16 path , pushes the module
+	OP_ITER     // u8 pair , replaces the iterable with its iterator
+	OP_FOR_NEXT // u16 forward offset taken at the end , else pushes the next element
+	OP_YIELD    // suspends the generator VM
+)
+
+var opNames = [...]string{
+	OP_CONSTANT:      "OP_CONSTANT",
+	OP_NIL:           "OP_NIL",
+	OP_TRUE:          "OP_TRUE",
+	OP_FALSE:         "OP_FALSE",
+	OP_UNINIT:        "OP_UNINIT",
+	OP_POP:           "OP_POP",
+	OP_GET_LOCAL:     "OP_GET_LOCAL",
+	OP_SET_LOCAL:     "OP_SET_LOCAL",
+	OP_GET_GLOBAL:    "OP_GET_GLOBAL",
+	OP_DEFINE_GLOBAL: "OP_DEFINE_GLOBAL",
+	OP_SET_GLOBAL:    "OP_SET_GLOBAL",
+	OP_GET_UPVALUE:   "OP_GET_UPVALUE",
+	OP_SET_UPVALUE:   "OP_SET_UPVALUE",
+	OP_GET_PROPERTY:  "OP_GET_PROPERTY",
+	OP_SET_PROPERTY:  "OP_SET_PROPERTY",
+	OP_GET_SUPER:     "OP_GET_SUPER",
+	OP_INDEX_GET:     "OP_INDEX_GET",
+	OP_INDEX_SET:     "OP_INDEX_SET",
+	OP_EQUAL:         "OP_EQUAL",
+	OP_NOT_EQUAL:     "OP_NOT_EQUAL",
+	OP_GREATER:       "OP_GREATER",
+	OP_GREATER_EQUAL: "OP_GREATER_EQUAL",
+	OP_LESS:          "OP_LESS",
+	OP_LESS_EQUAL:    "OP_LESS_EQUAL",
+	OP_ADD:           "OP_ADD",
+	OP_SUBTRACT:      "OP_SUBTRACT",
+	OP_MULTIPLY:      "OP_MULTIPLY",
+	OP_DIVIDE:        "OP_DIVIDE",
+	OP_FLOOR_DIVIDE:  "OP_FLOOR_DIVIDE",
+	OP_MODULO:        "OP_MODULO",
+	OP_POWER:         "OP_POWER",
+	OP_BIT_AND:       "OP_BIT_AND",
+	OP_BIT_OR:        "OP_BIT_OR",
+	OP_BIT_XOR:       "OP_BIT_XOR",
+	OP_SHIFT_LEFT:    "OP_SHIFT_LEFT",
+	OP_SHIFT_RIGHT:   "OP_SHIFT_RIGHT",
+	OP_BIT_NOT:       "OP_BIT_NOT",
+	OP_NOT:           "OP_NOT",
+	OP_NEGATE:        "OP_NEGATE",
+	OP_PRINT:         "OP_PRINT",
+	OP_JUMP:          "OP_JUMP",
+	OP_JUMP_IF_FALSE: "OP_JUMP_IF_FALSE",
+	OP_LOOP:          "OP_LOOP",
+	OP_CALL:          "OP_CALL",
+	OP_CLOSURE:       "OP_CLOSURE",
+	OP_CLOSE_UPVALUE: "OP_CLOSE_UPVALUE",
+	OP_RETURN:        "OP_RETURN",
+	OP_LIST:          "OP_LIST",
+	OP_MAP:           "OP_MAP",
+	OP_CLASS:         "OP_CLASS",
+	OP_INHERIT:       "OP_INHERIT",
+	OP_METHOD:        "OP_METHOD",
+	OP_INTERPOLATE:   "OP_INTERPOLATE",
+	OP_DUP:           "OP_DUP",
+	OP_ROTATE:        "OP_ROTATE",
+	OP_COMPOUND:      "OP_COMPOUND",
+	OP_TRY:           "OP_TRY",
+	OP_TRY_FINALLY:   "OP_TRY_FINALLY",
+	OP_END_TRY:       "OP_END_TRY",
+	OP_THROW:         "OP_THROW",
+	OP_RETHROW:       "OP_RETHROW",
+	OP_IMPORT:        "OP_IMPORT",
+	OP_ITER:          "OP_ITER",
+	OP_FOR_NEXT:      "OP_FOR_NEXT",
+	OP_YIELD:         "OP_YIELD",
+}
+
+func (op opcode) String() string {
+	if int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("OP_UNKNOWN(%d)", byte(op))
+}
+
+// Chunk is the compiled body of one function
+type Chunk struct {
+	code      []byte
+	spans     []Span // source position of every byte in code , for error display
+	constants []value
+}
+
+func (c *Chunk) write(b byte, span Span) {
+	c.code = append(c.code, b)
+	c.spans = append(c.spans, span)
+}
+
+func (c *Chunk) addConstant(v value) int {
+	// reuse the slot of an equal number or string , names repeat a lot
+	switch v.(type) {
+	case int64, float64, string:
+		for i, k := range c.constants {
+			if k == v {
+				return i
+			}
+		}
+	}
+	c.constants = append(c.constants, v)
+	return len(c.constants) - 1
+}
+
+func (c *Chunk) readShort(offset int) int {
+	return int(c.code[offset])<<8 | int(c.code[offset+1])
+}
+
+// ------------------------------------------
+// disassembler , golox -vm -disasm script
+
+func disassemble(fn *vmFunction) string {
+	var b strings.Builder
+	name := fn.name
+	if name == "" {
+		name = "<lambda>"
+	}
+	fmt.Fprintf(&b, "== %v ==\n", name)
+	for offset := 0; offset < len(fn.chunk.code); {
+		offset = fn.chunk.disassembleInstruction(&b, offset)
+	}
+	// nested functions live in the constant pool
+	for _, k := range fn.chunk.constants {
+		if f, ok := k.(*vmFunction); ok {
+			b.WriteString("\n")
+			b.WriteString(disassemble(f))
+		}
+	}
+	return b.String()
+}
+
+func (c *Chunk) disassembleInstruction(b *strings.Builder, offset int) int {
+	fmt.Fprintf(b, "%04d ", offset)
+	if offset > 0 && c.spans[offset].Line == c.spans[offset-1].Line {
+		b.WriteString("   | ")
+	} else {
+		fmt.Fprintf(b, "%4d ", c.spans[offset].Line)
+	}
+
+	op := opcode(c.code[offset])
+	switch op {
+	case OP_CONSTANT, OP_UNINIT, OP_GET_GLOBAL, OP_DEFINE_GLOBAL, OP_SET_GLOBAL,
+		OP_GET_PROPERTY, OP_SET_PROPERTY, OP_GET_SUPER, OP_CLASS, OP_METHOD, OP_IMPORT:
+		k := c.readShort(offset + 1)
+		fmt.Fprintf(b, "%-16v %4d '%v'\n", op, k, c.constants[k])
+		return offset + 3
+	case OP_GET_LOCAL, OP_SET_LOCAL, OP_GET_UPVALUE, OP_SET_UPVALUE, OP_CALL, OP_DUP, OP_ROTATE:
+		fmt.Fprintf(b, "%-16v %4d\n", op, c.code[offset+1])
+		return offset + 2
+	case OP_COMPOUND:
+		fmt.Fprintf(b, "%-16v %4v\n", op, token(c.code[offset+1]))
+		return offset + 2
+	case OP_LIST, OP_MAP, OP_INTERPOLATE:
+		fmt.Fprintf(b, "%-16v %4d\n", op, c.readShort(offset+1))
+		return offset + 3
+	case OP_JUMP, OP_JUMP_IF_FALSE, OP_TRY, OP_TRY_FINALLY, OP_FOR_NEXT:
+		jump := c.readShort(offset + 1)
+		fmt.Fprintf(b, "%-16v %4d -> %d\n", op, offset, offset+3+jump)
+		return offset + 3
+	case OP_LOOP:
+		jump := c.readShort(offset + 1)
+		fmt.Fprintf(b, "%-16v %4d -> %d\n", op, offset, offset+3-jump)
+		return offset + 3
+	case OP_CLOSURE:
+		k := c.readShort(offset + 1)
+		fn := c.constants[k].(*vmFunction)
+		fmt.Fprintf(b, "%-16v %4d %v\n", op, k, fn)
+		offset += 3
+		for i := 0; i < fn.upvalueCount; i++ {
+			kind := "upvalue"
+			if c.code[offset] == 1 {
+				kind = "local"
+			}
+			fmt.Fprintf(b, "%04d    |                     %v %d\n", offset, kind, c.code[offset+1])
+			offset += 2
+		}
+		return offset
+	default:
+		fmt.Fprintf(b, "%v\n", op)
+		return offset + 1
+	}
+}
@@ -0,0 +1,875 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+)
+
+// ------------------------------------------
+// compiler , walks the resolved AST and emits bytecode for the VM
+//
+// locals live in stack slots and captured variables become upvalues ,
+// the same layout as clox , so the compiler tracks its own scopes instead
+// of using the depths in locals
+
+const (
+	maxLocals   = math.MaxUint8 + 1
+	maxUpvalues = math.MaxUint8 + 1
+)
+
+type vmLocal struct {
+	name       string
+	depth      int // -1 while the initializer is being compiled
+	isCaptured bool
+}
+
+type vmUpvalueRef struct {
+	index   byte
+	isLocal bool
+}
+
+type vmLoop struct {
+	scopeDepth int   // scope depth outside the loop body
+	breaks     []int // forward jumps patched to the loop exit
+	continues  []int // forward jumps patched to the increment
+}
+
+// vmTry is a handler that is active while its try body compiles
+type vmTry struct {
+	loops   int    // len(c.loops) when the try began
+	finally []Stmt // nil for the handler of a catch
+}
+
+type classCompiler struct {
+	enclosing     *classCompiler
+	hasSuperclass bool
+}
+
+type compiler struct {
+	enclosing  *compiler
+	function   *vmFunction
+	kind       FunctionType
+	locals     []vmLocal
+	upvalues   []vmUpvalueRef
+	scopeDepth int
+	loops      []*vmLoop
+	tries      []*vmTry
+	class      *classCompiler
+	pos        Span     // span of the last token seen , for the line table
+	errs       *[]error // shared by nested compilers
+}
+
+// compile turns a whole program into the top-level script function
+func compile(stmts []Stmt) (fn *vmFunction, errs []error) {
+	errs = make([]error, 0)
+	c := newCompiler(nil, FT_NONE, "script", &errs)
+	for i, s := range stmts {
+		if es, ok := s.(*ExprStmt); ok && i == len(stmts)-1 {
+			// the value of a trailing expression is the result of the script
+			c.expr(es.expression)
+			c.emitOp(OP_RETURN)
+			break
+		}
+		c.stmt(s)
+	}
+	return c.end(), errs
+}
+
+func newCompiler(enclosing *compiler, kind FunctionType, name string, errs *[]error) *compiler {
+	c := &compiler{
+		enclosing: enclosing,
+		function:  &vmFunction{name: name},
+		kind:      kind,
+		locals:    make([]vmLocal, 0, 8),
+		pos:       Span{Line: 1},
+		errs:      errs,
+	}
+	if enclosing != nil {
+		c.class = enclosing.class
+		c.pos = enclosing.pos
+	}
+	// slot 0 holds the callee , or the receiver inside methods
+	slot0 := ""
+	if kind == FT_METHOD || kind == FT_INITIALIZER {
+		slot0 = "this"
+	}
+	c.locals = append(c.locals, vmLocal{name: slot0})
+	return c
+}
+
+func (c *compiler) end() *vmFunction {
+	c.emitReturn()
+	c.function.upvalueCount = len(c.upvalues)
+	return c.function
+}
+
+func (c *compiler) error(t *tokenObj, code, msg string) {
+	*c.errs = append(*c.errs, diagnosticAt(t, code, msg))
+}
+
+// ------------------------------------------
+// emit helpers
+
+func (c *compiler) at(t *tokenObj) {
+	if t != nil {
+		c.pos = t.span
+	}
+}
+
+func (c *compiler) emit(bytes ...byte) {
+	for _, b := range bytes {
+		c.function.chunk.write(b, c.pos)
+	}
+}
+
+func (c *compiler) emitOp(op opcode) {
+	c.emit(byte(op))
+}
+
+func (c *compiler) emitShort(op opcode, n int) {
+	c.emit(byte(op), byte(n>>8), byte(n))
+}
+
+func (c *compiler) makeConstant(v value) int {
+	k := c.function.chunk.addConstant(v)
+	if k > math.MaxUint16 {
+		c.error(&tokenObj{span: c.pos, lexeme: fmt.Sprintf("%v", v)}, C_TOO_MANY_CONSTANTS, "too many constants in one chunk")
+		return 0
+	}
+	return k
+}
+
+func (c *compiler) emitConstant(v value) {
+	c.emitShort(OP_CONSTANT, c.makeConstant(v))
+}
+
+func (c *compiler) emitJump(op opcode) int {
+	c.emit(byte(op), 0xff, 0xff)
+	return len(c.function.chunk.code) - 2
+}
+
+func (c *compiler) patchJump(offset int) {
+	jump := len(c.function.chunk.code) - offset - 2
+	if jump > math.MaxUint16 {
+		c.error(&tokenObj{span: c.pos}, C_JUMP_TOO_FAR, "too much code to jump over")
+	}
+	c.function.chunk.code[offset] = byte(jump >> 8)
+	c.function.chunk.code[offset+1] = byte(jump)
+}
+
+func (c *compiler) emitLoop(start int) {
+	jump := len(c.function.chunk.code) - start + 3
+	if jump > math.MaxUint16 {
+		c.error(&tokenObj{span: c.pos}, C_LOOP_TOO_LARGE, "loop body too large")
+	}
+	c.emitShort(OP_LOOP, jump)
+}
+
+func (c *compiler) emitReturn() {
+	if c.kind == FT_INITIALIZER {
+		c.emit(byte(OP_GET_LOCAL), 0) // init() always returns this
+	} else {
+		c.emitOp(OP_NIL)
+	}
+	c.emitOp(OP_RETURN)
+}
+
+// ------------------------------------------
+// scopes and variables
+
+func (c *compiler) beginScope() {
+	c.scopeDepth++
+}
+
+func (c *compiler) endScope() {
+	c.scopeDepth--
+	for len(c.locals) > 0 && c.locals[len(c.locals)-1].depth > c.scopeDepth {
+		c.popLocal(c.locals[len(c.locals)-1])
+		c.locals = c.locals[:len(c.locals)-1]
+	}
+}
+
+func (c *compiler) popLocal(l vmLocal) {
+	if l.isCaptured {
+		c.emitOp(OP_CLOSE_UPVALUE)
+	} else {
+		c.emitOp(OP_POP)
+	}
+}
+
+func (c *compiler) declareVariable(name *tokenObj) {
+	if c.scopeDepth == 0 {
+		return // globals are late bound
+	}
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		l := c.locals[i]
+		if l.depth != -1 && l.depth < c.scopeDepth {
+			break
+		}
+		if l.name == name.lexeme {
+			c.error(name, S_ALREADY_DECLARED, "Already a variable with this name in this scope.")
+		}
+	}
+	c.addLocal(name)
+}
+
+func (c *compiler) addLocal(name *tokenObj) {
+	if len(c.locals) == maxLocals {
+		c.error(name, C_TOO_MANY_LOCALS, "too many local variables in function")
+		return
+	}
+	c.locals = append(c.locals, vmLocal{name: name.lexeme, depth: -1})
+}
+
+func (c *compiler) markInitialized() {
+	if c.scopeDepth == 0 {
+		return
+	}
+	c.locals[len(c.locals)-1].depth = c.scopeDepth
+}
+
+// defineVariable finishes a declaration , the value is on top of the stack
+func (c *compiler) defineVariable(name *tokenObj) {
+	if c.scopeDepth > 0 {
+		c.markInitialized()
+		return
+	}
+	c.emitShort(OP_DEFINE_GLOBAL, c.makeConstant(name.lexeme))
+}
+
+func (c *compiler) resolveLocal(name *tokenObj) int {
+	for i := len(c.locals) - 1; i >= 0; i-- {
+		if c.locals[i].name == name.lexeme {
+			if c.locals[i].depth == -1 {
+				c.error(name, S_OWN_INITIALIZER, "Can't read local variable in its own initializer.")
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *compiler) resolveUpvalue(name *tokenObj) int {
+	if c.enclosing == nil {
+		return -1
+	}
+	if local := c.enclosing.resolveLocal(name); local != -1 {
+		c.enclosing.locals[local].isCaptured = true
+		return c.addUpvalue(name, byte(local), true)
+	}
+	if up := c.enclosing.resolveUpvalue(name); up != -1 {
+		return c.addUpvalue(name, byte(up), false)
+	}
+	return -1
+}
+
+func (c *compiler) addUpvalue(name *tokenObj, index byte, isLocal bool) int {
+	for i, u := range c.upvalues {
+		if u.index == index && u.isLocal == isLocal {
+			return i
+		}
+	}
+	if len(c.upvalues) == maxUpvalues {
+		c.error(name, C_TOO_MANY_UPVALUES, "too many closure variables in function")
+		return 0
+	}
+	c.upvalues = append(c.upvalues, vmUpvalueRef{index: index, isLocal: isLocal})
+	return len(c.upvalues) - 1
+}
+
+// namedVariable emits a load , or a store of the value on top of the stack
+func (c *compiler) namedVariable(name *tokenObj, set bool) {
+	getOp, setOp := OP_GET_LOCAL, OP_SET_LOCAL
+	arg := c.resolveLocal(name)
+	if arg == -1 {
+		if arg = c.resolveUpvalue(name); arg != -1 {
+			getOp, setOp = OP_GET_UPVALUE, OP_SET_UPVALUE
+		}
+	}
+	if arg == -1 {
+		k := c.makeConstant(name.lexeme)
+		if set {
+			c.emitShort(OP_SET_GLOBAL, k)
+		} else {
+			c.emitShort(OP_GET_GLOBAL, k)
+		}
+		return
+	}
+	if set {
+		c.emit(byte(setOp), byte(arg))
+	} else {
+		c.emit(byte(getOp), byte(arg))
+	}
+}
+
+// ------------------------------------------
+// statements
+
+func (c *compiler) stmt(s Stmt) {
+	switch s := s.(type) {
+	case *ExprStmt:
+		c.expr(s.expression)
+		c.emitOp(OP_POP)
+	case *PrintStmt:
+		c.expr(s.expression)
+		c.emitOp(OP_PRINT)
+	case *VarStmt:
+		c.at(s.name)
+		c.declareVariable(s.name)
+		if s.init != nil {
+			c.expr(s.init)
+		} else {
+			// make distinction between uninitialized value and nil-value
+			c.emitShort(OP_UNINIT, c.makeConstant(s.name.lexeme))
+		}
+		c.defineVariable(s.name)
+	case *BlockStmt:
+		c.block(s.list)
+	case *IfStmt:
+		c.expr(s.condition)
+		thenJump := c.emitJump(OP_JUMP_IF_FALSE)
+		c.emitOp(OP_POP)
+		c.stmt(s.block1)
+		elseJump := c.emitJump(OP_JUMP)
+		c.patchJump(thenJump)
+		c.emitOp(OP_POP)
+		if s.block2 != nil {
+			c.stmt(s.block2)
+		}
+		c.patchJump(elseJump)
+	case *WhileStmt:
+		c.whileStmt(s)
+	case *ForInStmt:
+		c.forInStmt(s)
+	case *BreakStmt:
+		c.at(s.keyword)
+		loop := c.innermostLoop(s.keyword)
+		if loop != nil {
+			c.exitTries(len(c.loops))
+			c.discardLocals(loop.scopeDepth)
+			loop.breaks = append(loop.breaks, c.emitJump(OP_JUMP))
+		}
+	case *ContinueStmt:
+		c.at(s.keyword)
+		loop := c.innermostLoop(s.keyword)
+		if loop != nil {
+			c.exitTries(len(c.loops))
+			c.discardLocals(loop.scopeDepth)
+			loop.continues = append(loop.continues, c.emitJump(OP_JUMP))
+		}
+	case *FunStmt:
+		c.at(s.name)
+		c.declareVariable(s.name)
+		c.markInitialized() // allow recursion
+		c.compileFunction(s.name.lexeme, s.params, s.body, FT_FUNCTION, s.generator)
+		c.defineVariable(s.name)
+	case *ReturnStmt:
+		c.at(s.keyword)
+		if s.value == nil {
+			c.exitTries(0)
+			c.emitReturn()
+			return
+		}
+		if c.kind == FT_INITIALIZER {
+			c.error(s.keyword, S_INITIALIZER_RETURN, "Can't return a value from an initializer.")
+		}
+		c.expr(s.value)
+		if len(c.tries) > 0 {
+			// the value waits in a hidden local while the finally blocks run
+			c.hiddenLocal()
+			c.exitTries(0)
+			c.emitOp(OP_RETURN)
+			c.dropHidden()
+			return
+		}
+		c.emitOp(OP_RETURN)
+	case *ImportStmt:
+		c.importStmt(s)
+	case *YieldStmt:
+		if s.value == nil {
+			c.emitOp(OP_NIL)
+		} else {
+			c.expr(s.value)
+		}
+		c.at(s.keyword)
+		c.emitOp(OP_YIELD)
+	case *ThrowStmt:
+		c.expr(s.value)
+		c.at(s.keyword)
+		c.emitOp(OP_THROW)
+	case *TryStmt:
+		c.tryStmt(s)
+	case *ClassStmt:
+		c.classStmt(s)
+	default:
+		panic(fmt.Sprintf("vm compiler: unexpected statement %T", s))
+	}
+}
+
+func (c *compiler) whileStmt(s *WhileStmt) {
+	c.at(s.keyword)
+	loop := &vmLoop{scopeDepth: c.scopeDepth}
+	c.loops = append(c.loops, loop)
+
+	start := len(c.function.chunk.code)
+	c.expr(s.condition)
+	exitJump := c.emitJump(OP_JUMP_IF_FALSE)
+	c.emitOp(OP_POP)
+	c.stmt(s.body)
+
+	for _, j := range loop.continues {
+		c.patchJump(j)
+	}
+	if s.increment != nil {
+		c.expr(s.increment)
+		c.emitOp(OP_POP)
+	}
+	c.at(s.keyword) // limit errors point at the loop
+	c.emitLoop(start)
+
+	c.patchJump(exitJump)
+	c.emitOp(OP_POP) // the condition
+	for _, j := range loop.breaks {
+		c.patchJump(j)
+	}
+	c.loops = c.loops[:len(c.loops)-1]
+}
+
+// forInStmt keeps the iterator in a hidden local below the loop variables ,
+// which are popped , or closed over , at the end of every iteration
+func (c *compiler) forInStmt(s *ForInStmt) {
+	c.expr(s.iterable)
+	c.at(s.keyword)
+	pair := byte(0)
+	if s.key != nil {
+		pair = 1
+	}
+	c.emit(byte(OP_ITER), pair)
+	c.hiddenLocal()
+	loop := &vmLoop{scopeDepth: c.scopeDepth}
+	c.loops = append(c.loops, loop)
+
+	start := len(c.function.chunk.code)
+	exitJump := c.emitJump(OP_FOR_NEXT)
+	c.beginScope()
+	if s.key != nil {
+		c.addLocal(s.key)
+		c.markInitialized()
+	}
+	c.addLocal(s.name)
+	c.markInitialized()
+	c.stmt(s.body)
+	c.endScope()
+
+	for _, j := range loop.continues {
+		c.patchJump(j)
+	}
+	c.at(s.keyword) // limit errors point at the loop
+	c.emitLoop(start)
+
+	c.patchJump(exitJump)
+	for _, j := range loop.breaks {
+		c.patchJump(j)
+	}
+	c.loops = c.loops[:len(c.loops)-1]
+	c.endScope() // the iterator
+}
+
+func (c *compiler) innermostLoop(keyword *tokenObj) *vmLoop {
+	if len(c.loops) == 0 {
+		c.error(keyword, S_BREAK_OUTSIDE_LOOP, "expected inside the loop")
+		return nil
+	}
+	return c.loops[len(c.loops)-1]
+}
+
+// importStmt imports once per name , the module is cached after the first
+func (c *compiler) importStmt(s *ImportStmt) {
+	path := c.makeConstant(s.path.literal.(string))
+	if s.name != nil {
+		c.declareVariable(s.name)
+		c.at(s.keyword)
+		c.emitShort(OP_IMPORT, path)
+		c.defineVariable(s.name)
+		return
+	}
+	for i, name := range s.exports {
+		c.declareVariable(s.aliases[i])
+		c.at(s.keyword)
+		c.emitShort(OP_IMPORT, path)
+		c.at(name)
+		c.emitShort(OP_GET_PROPERTY, c.makeConstant(name.lexeme))
+		c.defineVariable(s.aliases[i])
+	}
+}
+
+// tryStmt compiles try { } catch (e) { } finally { } like
+// try { try { } catch (e) { } } finally { } , the finally block is copied to
+// the normal exit , to the handler that raises the error again and to every
+// break , continue and return leaving the try
+func (c *compiler) tryStmt(s *TryStmt) {
+	c.at(s.keyword)
+	var finallyHandler int
+	if s.finally != nil {
+		finallyHandler = c.emitJump(OP_TRY_FINALLY)
+		c.tries = append(c.tries, &vmTry{loops: len(c.loops), finally: s.finally})
+	}
+	if s.name != nil {
+		catchHandler := c.emitJump(OP_TRY)
+		c.tries = append(c.tries, &vmTry{loops: len(c.loops)})
+		c.block(s.body)
+		c.tries = c.tries[:len(c.tries)-1]
+		c.emitOp(OP_END_TRY)
+		skip := c.emitJump(OP_JUMP)
+		c.patchJump(catchHandler)
+		// the caught value is on top of the stack , it is the catch variable
+		c.beginScope()
+		c.addLocal(s.name)
+		c.markInitialized()
+		for _, st := range s.catch {
+			c.stmt(st)
+		}
+		c.endScope()
+		c.patchJump(skip)
+	} else {
+		c.block(s.body)
+	}
+	if s.finally != nil {
+		c.tries = c.tries[:len(c.tries)-1]
+		c.emitOp(OP_END_TRY)
+		c.block(s.finally)
+		done := c.emitJump(OP_JUMP)
+		c.patchJump(finallyHandler)
+		c.hiddenLocal() // the pending error
+		c.block(s.finally)
+		c.emitOp(OP_RETHROW)
+		c.dropHidden()
+		c.patchJump(done)
+	}
+}
+
+// exitTries leaves the handlers that a jump out of the loop at depth loops
+// skips , running their finally blocks on the way. return leaves them all
+func (c *compiler) exitTries(loops int) {
+	for i := len(c.tries) - 1; i >= 0 && c.tries[i].loops >= loops; i-- {
+		c.emitOp(OP_END_TRY)
+		if finally := c.tries[i].finally; finally != nil {
+			tries := c.tries
+			c.tries = c.tries[:i] // a jump out of finally must not run it again
+			c.block(finally)
+			c.tries = tries
+		}
+	}
+}
+
+// hiddenLocal names the value on top of the stack , so that the locals
+// compiled after it get the right slots
+func (c *compiler) hiddenLocal() {
+	c.beginScope()
+	c.addLocal(&tokenObj{})
+	c.markInitialized()
+}
+
+// dropHidden forgets the hidden local without a pop , the code before it
+// leaves by return or by raising an error
+func (c *compiler) dropHidden() {
+	c.scopeDepth--
+	c.locals = c.locals[:len(c.locals)-1]
+}
+
+func (c *compiler) block(list []Stmt) {
+	c.beginScope()
+	for _, st := range list {
+		c.stmt(st)
+	}
+	c.endScope()
+}
+
+// discardLocals pops the locals deeper than depth without leaving their scope ,
+// break and continue jump out of the middle of a block
+func (c *compiler) discardLocals(depth int) {
+	for i := len(c.locals) - 1; i >= 0 && c.locals[i].depth > depth; i-- {
+		c.popLocal(c.locals[i])
+	}
+}
+
+// compileFunction compiles a function body and emits the closure creating it
+func (c *compiler) compileFunction(name string, params []*tokenObj, body []Stmt, kind FunctionType, generator bool) {
+	fc := newCompiler(c, kind, name, c.errs)
+	fc.function.generator = generator
+	fc.beginScope()
+	for _, p := range params {
+		fc.declareVariable(p)
+		fc.markInitialized()
+		fc.function.params = append(fc.function.params, p.lexeme)
+	}
+	fc.function.arity = len(params)
+	for _, s := range body {
+		fc.stmt(s)
+	}
+	fn := fc.end()
+	c.pos = fc.pos
+
+	c.emitShort(OP_CLOSURE, c.makeConstant(fn))
+	for _, u := range fc.upvalues {
+		isLocal := byte(0)
+		if u.isLocal {
+			isLocal = 1
+		}
+		c.emit(isLocal, u.index)
+	}
+}
+
+func (c *compiler) classStmt(s *ClassStmt) {
+	c.at(s.name)
+	k := c.makeConstant(s.name.lexeme)
+	c.declareVariable(s.name)
+	c.emitShort(OP_CLASS, k)
+	c.defineVariable(s.name)
+
+	class := &classCompiler{enclosing: c.class}
+	c.class = class
+
+	if s.superClass != nil {
+		if s.superClass.name.lexeme == s.name.lexeme {
+			c.error(s.superClass.name, S_INHERIT_ITSELF, "A class can't inherit from itself.")
+		}
+		c.namedVariable(s.superClass.name, false)
+
+		// methods close over an extra scope that holds "super"
+		c.beginScope()
+		c.addLocal(&tokenObj{tok: Super, lexeme: "super", span: s.name.span})
+		c.markInitialized()
+
+		c.namedVariable(s.name, false)
+		c.at(s.superClass.name) // "Superclass must be a class." points here
+		c.emitOp(OP_INHERIT)
+		class.hasSuperclass = true
+	}
+
+	c.namedVariable(s.name, false)
+	for _, m := range s.methods {
+		c.at(m.name)
+		kind := FunctionType(FT_METHOD)
+		if m.name.lexeme == "init" {
+			kind = FT_INITIALIZER
+		}
+		c.compileFunction(m.name.lexeme, m.params, m.body, kind, m.generator)
+		c.emitShort(OP_METHOD, c.makeConstant(m.name.lexeme))
+	}
+	c.emitOp(OP_POP) // the class
+
+	if class.hasSuperclass {
+		c.endScope()
+	}
+	c.class = class.enclosing
+}
+
+// ------------------------------------------
+// expressions
+
+func (c *compiler) expr(e Expr) {
+	switch e := e.(type) {
+	case *LiteralExpr:
+		switch v := e.value.(type) {
+		case nil:
+			c.emitOp(OP_NIL)
+		case bool:
+			if v {
+				c.emitOp(OP_TRUE)
+			} else {
+				c.emitOp(OP_FALSE)
+			}
+		default:
+			c.emitConstant(v)
+		}
+	case *GroupingExpr:
+		c.expr(e.expression)
+	case *UnaryExpr:
+		c.expr(e.right)
+		c.at(e.operator)
+		switch e.operator.tok {
+		case Minus:
+			c.emitOp(OP_NEGATE)
+		case Bang:
+			c.emitOp(OP_NOT)
+		case Tilde:
+			c.emitOp(OP_BIT_NOT)
+		}
+	case *BinaryExpr:
+		c.expr(e.left)
+		c.expr(e.right)
+		c.at(e.operator)
+		c.emitOp(binaryOps[e.operator.tok])
+	case *LogicalExpr:
+		c.expr(e.left)
+		c.at(e.operator)
+		if e.operator.tok == Or {
+			elseJump := c.emitJump(OP_JUMP_IF_FALSE)
+			endJump := c.emitJump(OP_JUMP)
+			c.patchJump(elseJump)
+			c.emitOp(OP_POP)
+			c.expr(e.right)
+			c.patchJump(endJump)
+		} else {
+			endJump := c.emitJump(OP_JUMP_IF_FALSE)
+			c.emitOp(OP_POP)
+			c.expr(e.right)
+			c.patchJump(endJump)
+		}
+	case *TernaryExpr:
+		c.expr(e.op1)
+		c.at(e.operator)
+		elseJump := c.emitJump(OP_JUMP_IF_FALSE)
+		c.emitOp(OP_POP)
+		c.expr(e.op2)
+		endJump := c.emitJump(OP_JUMP)
+		c.patchJump(elseJump)
+		c.emitOp(OP_POP)
+		c.expr(e.op3)
+		c.patchJump(endJump)
+	case *VarExpr:
+		c.at(e.name)
+		c.namedVariable(e.name, false)
+	case *AssignExpr:
+		if e.op == nil {
+			c.expr(e.value)
+		} else {
+			c.at(e.name)
+			c.namedVariable(e.name, false)
+			c.compound(e.op, e.value, e.postfix, 0)
+		}
+		c.at(e.name)
+		c.namedVariable(e.name, true)
+		if e.postfix {
+			c.emitOp(OP_POP)
+		}
+	case *CallExpr:
+		c.expr(e.callee)
+		for _, a := range e.args {
+			c.expr(a)
+		}
+		c.at(e.paren)
+		c.emit(byte(OP_CALL), byte(len(e.args)))
+	case *FunExpr:
+		c.compileFunction("", e.params, e.body, FT_FUNCTION, e.generator)
+	case *GetExpr:
+		c.expr(e.object)
+		c.at(e.name)
+		c.emitShort(OP_GET_PROPERTY, c.makeConstant(e.name.lexeme))
+	case *SetExpr:
+		c.expr(e.object)
+		if e.op == nil {
+			c.expr(e.vlue)
+		} else {
+			c.emit(byte(OP_DUP), 1)
+			c.at(e.name)
+			c.emitShort(OP_GET_PROPERTY, c.makeConstant(e.name.lexeme))
+			c.compound(e.op, e.vlue, e.postfix, 1)
+		}
+		c.at(e.name)
+		c.emitShort(OP_SET_PROPERTY, c.makeConstant(e.name.lexeme))
+		if e.postfix {
+			c.emitOp(OP_POP)
+		}
+	case *ThisExpr:
+		c.at(e.keyword)
+		if c.class == nil {
+			c.error(e.keyword, S_THIS_OUTSIDE_CLASS, "Can't use 'this' outside of a class.")
+			return
+		}
+		c.namedVariable(e.keyword, false)
+	case *SuperExpr:
+		c.at(e.keyword)
+		if c.class == nil {
+			c.error(e.keyword, S_SUPER_OUTSIDE_CLASS, "Can't use 'super' outside of a class.")
+			return
+		} else if !c.class.hasSuperclass {
+			c.error(e.keyword, S_SUPER_NO_SUPERCLASS, "Can't use 'super' in a class with no superclass.")
+			return
+		}
+		c.namedVariable(&tokenObj{tok: This, lexeme: "this", span: e.keyword.span}, false)
+		c.namedVariable(e.keyword, false)
+		c.emitShort(OP_GET_SUPER, c.makeConstant(e.method.lexeme))
+	case *InterpolationExpr:
+		for _, part := range e.parts {
+			c.expr(part)
+		}
+		c.emitShort(OP_INTERPOLATE, len(e.parts))
+	case *ListExpr:
+		c.at(e.bracket)
+		for _, el := range e.elements {
+			c.expr(el)
+		}
+		c.emitShort(OP_LIST, len(e.elements))
+	case *MapExpr:
+		c.at(e.brace)
+		for i := range e.keys {
+			c.expr(e.keys[i])
+			c.expr(e.values[i])
+		}
+		c.emitShort(OP_MAP, len(e.keys))
+	case *IndexGetExpr:
+		c.expr(e.object)
+		c.expr(e.index)
+		c.at(e.bracket)
+		c.emitOp(OP_INDEX_GET)
+	case *IndexSetExpr:
+		c.expr(e.object)
+		c.expr(e.index)
+		if e.op == nil {
+			c.expr(e.value)
+		} else {
+			c.emit(byte(OP_DUP), 2)
+			c.at(e.bracket)
+			c.emitOp(OP_INDEX_GET)
+			c.compound(e.op, e.value, e.postfix, 2)
+		}
+		c.at(e.bracket)
+		c.emitOp(OP_INDEX_SET)
+		if e.postfix {
+			c.emitOp(OP_POP)
+		}
+	default:
+		panic(fmt.Sprintf("vm compiler: unexpected expression %T", e))
+	}
+}
+
+// compound emits the new value of a compound assignment target , the old
+// value is on top of the stack with depth values for the target under it ,
+// like the object and the index. for x++ a copy of the old value is moved
+// below them , so that it is left once the new value is stored and popped
+func (c *compiler) compound(op *tokenObj, value Expr, postfix bool, depth int) {
+	if postfix {
+		c.emit(byte(OP_DUP), 1)
+		if depth > 0 {
+			c.emit(byte(OP_ROTATE), byte(depth+1))
+		}
+	}
+	if value != nil {
+		c.expr(value)
+	}
+	c.at(op)
+	c.emit(byte(OP_COMPOUND), byte(op.tok))
+}
+
+var binaryOps = map[token]opcode{
+	Plus:           OP_ADD,
+	Minus:          OP_SUBTRACT,
+	Star:           OP_MULTIPLY,
+	Slash:          OP_DIVIDE,
+	Div:            OP_FLOOR_DIVIDE,
+	Percent:        OP_MODULO,
+	StarStar:       OP_POWER,
+	Amp:            OP_BIT_AND,
+	Pipe:           OP_BIT_OR,
+	Caret:          OP_BIT_XOR,
+	LessLess:       OP_SHIFT_LEFT,
+	GreaterGreater: OP_SHIFT_RIGHT,
+	EqualEqual:     OP_EQUAL,
+	BangEqual:      OP_NOT_EQUAL,
+	Greater:        OP_GREATER,
+	GreaterEqual:   OP_GREATER_EQUAL,
+	Less:           OP_LESS,
+	LessEqual:      OP_LESS_EQUAL,
+}
@@ -0,0 +1,23 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestCompoundAssignment(t *testing.T) {
+	src := `
+var i = 10; i += 5; i -= 1; i *= 2; i %= 5; print i;
+print i++; print i; print ++i; print i--; print --i;
+class P {} var p = P(); p.n = 1;
+var calls = 0; fun get() { calls++; return p; }
+get().n += 10; print get().n++; print p.n; print calls;
+var xs = [1, 2, 3]; var k = 0; fun idx() { k++; return 1; }
+xs[idx()] *= 5; print xs[idx()]--; print xs; print k;
+var s = "n="; s += 1; print s;
+`
+	want := "3\n3\n4\n5\n5\n3\n11\n12\n2\n10\n[1, 9, 3]\n2\nn=1\n"
+	runBoth(t, src, want, lox.Options{})
+	failBoth(t, `var s = "n"; s++;`, "[line 1] runtime error: operand must be a number", lox.Options{})
+}
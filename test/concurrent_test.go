@@ -0,0 +1,60 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+const counterScript = `
+fun makeCounter(start) {
+  var i = start;
+  fun count() {
+    i = i + 1;
+    return i;
+  }
+  return count;
+}
+
+class Acc {
+  init() { this.total = 0; }
+  add(n) { this.total = this.total + n; }
+}
+
+var c = makeCounter(seed);
+var acc = Acc();
+for (var k = 0; k < 100; k = k + 1) {
+  acc.add(c());
+}
+print acc.total;
+acc.total;
+`
+
+// run with go test -race , every interpreter must keep its own state
+func TestConcurrentInterpreters(t *testing.T) {
+	for _, vm := range []bool{false, true} {
+		var wg sync.WaitGroup
+		for n := 0; n < 32; n++ {
+			wg.Add(1)
+			go func(seed float64) {
+				defer wg.Done()
+				var out bytes.Buffer
+				in := lox.NewInterpreter(lox.Options{Stdout: &out, VM: vm})
+				in.Define("seed", seed)
+				v, err := in.Eval(counterScript)
+				if err != nil {
+					t.Errorf("vm=%v seed=%v: %v", vm, seed, err)
+					return
+				}
+				want := 100*seed + 5050
+				if v != want || out.String() != fmt.Sprintf("%v\n", want) {
+					t.Errorf("vm=%v seed=%v: got %v , printed %q , want %v", vm, seed, v, out.String(), want)
+				}
+			}(float64(n))
+		}
+		wg.Wait()
+	}
+}
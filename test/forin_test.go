@@ -0,0 +1,40 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestForIn(t *testing.T) {
+	src := `
+for (i, x in ["a", "b"]) print str(i) + x;
+for (k, v in {"one": 1, "two": 2}) print k + "=" + v;
+for (c in "hé") print c;
+for (i in range(10, 0, -4)) print i;
+for (i in range(10)) {
+  if (i == 1) continue;
+  if (i == 3) break;
+  print i;
+}
+class Countdown {
+  init(n) { this.n = n; }
+  iter() { return this; }
+  next() {
+    if (this.n == 0) return nil;
+    this.n = this.n - 1;
+    return this.n + 1;
+  }
+}
+var fns = [];
+for (n in Countdown(2)) fns.push(fun() { return n; });
+for (f in fns) print f();
+var in = "in is still a name";
+print in;
+`
+	want := "0a\n1b\none=1\ntwo=2\nh\né\n10\n6\n2\n0\n2\n2\n1\nin is still a name\n"
+	opts := lox.Options{MaxSteps: 1000}
+	runBoth(t, src, want, opts)
+	failBoth(t, "\nfor (x in 5) {}", "[line 2] runtime error: number is not iterable", opts)
+	failBoth(t, "\nfor (i in range(1000000)) {}", "[line 2] runtime error: step budget exceeded", opts)
+}
@@ -0,0 +1,47 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestGenerator(t *testing.T) {
+	src := `
+fun count(n) {
+  var i = 0;
+  while (i < n) { yield i; i = i + 1; }
+  return "dropped";
+}
+for (x in count(3)) print x;
+var g = count(1);
+print g;
+print g.next(); print g.next(); print g.next();
+fun nils() { yield; yield 1; }
+for (x in nils()) print x;
+fun peek() { var x = 1; yield fun() { return x; }; x = 2; yield nil; }
+var p = peek();
+var get = p.next();
+p.next();
+print get();
+fun guarded() { try { yield 1; [][0]; } catch (e) { yield e.message; } finally { print "finally"; } }
+for (x in guarded()) print x;
+fun bad() { yield 1; throw "bad"; }
+try { for (x in bad()) print x; } catch (e) { print "caught " + e; }
+var self;
+fun selfish() { yield self.next(); }
+self = selfish();
+try { self.next(); } catch (e) { print e.message; }
+class Pair {
+  init(a, b) { this.a = a; this.b = b; }
+  each() { yield this.a; yield this.b; }
+}
+for (x in Pair("a", "b").each()) print x;
+`
+	want := "0\n1\n2\n<generator count>\n0\nnil\nnil\nnil\n1\n2\n1\nlist index 0 out of range for length 0\nfinally\n" +
+		"1\ncaught bad\ngenerator is already running\na\nb\n"
+	runBoth(t, src, want, lox.Options{MaxSteps: 1000})
+	failBoth(t, "fun rec() { for (x in rec()) yield x; }\nfor (x in rec()) {}",
+		"[line 1] runtime error: stack overflow", lox.Options{MaxDepth: 50})
+	failBoth(t, `yield 1;`, "[line 1] error at 'yield': Can't yield from top-level code.", lox.Options{})
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+// evalBoth evaluates src as file on the tree-walker and then on the VM , a
+// fresh interpreter each , check gets what it printed and the error
+func evalBoth(t *testing.T, file, src string, opts lox.Options, check func(vm bool, printed string, err error)) {
+	t.Helper()
+	for _, vm := range []bool{false, true} {
+		var out bytes.Buffer
+		opts.Stdout, opts.VM = &out, vm
+		in := lox.NewInterpreter(opts)
+		_, err := in.EvalFile(file, src)
+		check(vm, out.String(), err)
+	}
+}
+
+// runBoth checks that src runs without errors and prints want on both backends
+func runBoth(t *testing.T, src, want string, opts lox.Options) {
+	t.Helper()
+	evalBoth(t, "", src, opts, func(vm bool, printed string, err error) {
+		if err != nil || printed != want {
+			t.Errorf("vm=%v: got %v , printed %q", vm, err, printed)
+		}
+	})
+}
+
+// failBoth checks that src stops with wantErr on both backends before it
+// printed anything
+func failBoth(t *testing.T, src, wantErr string, opts lox.Options) {
+	t.Helper()
+	evalBoth(t, "", src, opts, func(vm bool, printed string, err error) {
+		if err == nil || err.Error() != wantErr || printed != "" {
+			t.Errorf("vm=%v: %q got %v , printed %q", vm, src, err, printed)
+		}
+	})
+}
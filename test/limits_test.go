@@ -0,0 +1,68 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestLimits(t *testing.T) {
+	for _, vm := range []bool{false, true} {
+		in := lox.NewInterpreter(lox.Options{VM: vm, MaxSteps: 10000, MaxDepth: 100})
+
+		_, err := in.Eval("\nwhile (true) {}")
+		if err == nil || err.Error() != "[line 2] runtime error: step budget exceeded" {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+
+		_, err = in.Eval("fun f(n) { return f(n + 1); }\nf(0);")
+		if err == nil || err.Error() != "[line 1] runtime error: stack overflow" {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+
+		// the budget is per Eval , and the depth is back to 0 after the error
+		v, err := in.Eval("fun g(n) { if (n == 0) return 0; return g(n - 1); } var i = 0; for (; i < 1000; i = i + 1) {} g(99);")
+		if err != nil || v != int64(0) {
+			t.Errorf("vm=%v: got %v %v", vm, v, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		in = lox.NewInterpreter(lox.Options{VM: vm})
+		_, err = in.EvalContext(ctx, "for (;;) {}")
+		cancel()
+		if err == nil || !strings.HasSuffix(err.Error(), "execution cancelled: context deadline exceeded") {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+	}
+}
+
+// a Go function calling back into Lox stays under the budget and ctx of the
+// Eval it runs in
+func TestLimitsThroughCallbacks(t *testing.T) {
+	src := "fun f() {}\nfor (var i = 0; i < 100000; i = i + 1) apply(f);"
+	for _, vm := range []bool{false, true} {
+		in := lox.NewInterpreter(lox.Options{VM: vm, MaxSteps: 1000})
+		apply := func(fn lox.Value) (lox.Value, error) { return in.Call(fn) }
+		if err := in.RegisterFunc("apply", apply); err != nil {
+			t.Fatal(err)
+		}
+		_, err := in.Eval(src)
+		if err == nil || !strings.HasSuffix(err.Error(), "step budget exceeded") {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+
+		in = lox.NewInterpreter(lox.Options{VM: vm})
+		if err := in.RegisterFunc("apply", func(fn lox.Value) (lox.Value, error) { return in.Call(fn) }); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = in.EvalContext(ctx, "fun f() {}\nfor (;;) apply(f);")
+		if err == nil || !strings.HasSuffix(err.Error(), "execution cancelled: context canceled") {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+	}
+}
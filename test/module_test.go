@@ -0,0 +1,46 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/counter.glx": `print "loading"; var n = 0; fun next() { n = n + 1; return n; }`,
+		"lib/a.glx":       `import "b.glx" as b;`,
+		"lib/b.glx":       `import "a.glx" as a;`,
+		"path/greet.glx":  `fun hi(name) { return "hi " + name; }`,
+	}
+	for name, src := range files {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755)
+		os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644)
+	}
+	main := filepath.Join(dir, "main.glx")
+	src := `
+import "lib/counter.glx" as counter;
+from "lib/counter.glx" import next, n as start;
+import "greet.glx" as greet;
+print counter.next(); print next(); print start;
+print greet.hi("lox");
+try { counter.nope; } catch (e) { print e.message; }
+`
+	want := "loading\n1\n2\n0\nhi lox\nmodule 'lib/counter.glx' has no export 'nope'\n"
+	opts := lox.Options{ImportPath: []string{filepath.Join(dir, "path")}}
+	evalBoth(t, main, src, opts, func(vm bool, printed string, err error) {
+		if err != nil || printed != want {
+			t.Errorf("vm=%v: got %v , printed %q", vm, err, printed)
+		}
+	})
+	cycle := "import cycle: " + filepath.Join(dir, "lib/a.glx") + " -> " + filepath.Join(dir, "lib/b.glx") + " -> " + filepath.Join(dir, "lib/a.glx")
+	evalBoth(t, main, `import "lib/a.glx" as a;`, opts, func(vm bool, _ string, err error) {
+		if err == nil || !strings.HasSuffix(err.Error(), cycle) {
+			t.Errorf("vm=%v: got %v", vm, err)
+		}
+	})
+}
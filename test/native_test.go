@@ -0,0 +1,72 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	for _, vm := range []bool{false, true} {
+		var out bytes.Buffer
+		in := lox.NewInterpreter(lox.Options{Stdout: &out, VM: vm})
+		must := func(err error) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		must(in.RegisterFunc("sqrt", math.Sqrt))
+		must(in.RegisterFunc("repeat", strings.Repeat))
+		must(in.RegisterFunc("sum", func(xs ...float64) float64 {
+			total := 0.0
+			for _, x := range xs {
+				total += x
+			}
+			return total
+		}))
+		must(in.RegisterFunc("isNil", func(v lox.Value) bool { return v == nil }))
+		must(in.RegisterFunc("fail", func(msg string) (int, error) { return 0, errors.New(msg) }))
+
+		v, err := in.Eval(`print sqrt(16); print repeat("ab", 3); print sum(); isNil(nil) and sum(1, 2, 3) == 6;`)
+		if err != nil || v != true || out.String() != "4\nababab\n0\n" {
+			t.Errorf("vm=%v: got %v %v , printed %q", vm, v, err, out.String())
+		}
+
+		for src, want := range map[string]string{
+			"\n\nfail(\"boom\");": "[line 3] runtime error: boom",
+			`repeat("ab");`:       "[line 1] runtime error: expected 2 arguments but got 1",
+			`repeat("ab", 1.5);`:  "[line 1] runtime error: argument 2 to 'repeat' must be an integer, got 1.5",
+			`sqrt("x");`:          "[line 1] runtime error: argument 1 to 'sqrt' must be a number, got string",
+			`repeat("a", -1);`:    "[line 1] runtime error: 'repeat' panicked: strings: negative Repeat count",
+		} {
+			if _, err := in.Eval(src); err == nil || err.Error() != want {
+				t.Errorf("vm=%v: %v got error %v , want %v", vm, src, err, want)
+			}
+		}
+	}
+
+	in := lox.NewInterpreter(lox.Options{})
+	if err := in.RegisterFunc("bad", 42); err == nil {
+		t.Error("expected an error registering a non-func")
+	}
+}
+
+func TestDefineConverts(t *testing.T) {
+	for _, vm := range []bool{false, true} {
+		in := lox.NewInterpreter(lox.Options{VM: vm})
+		in.Define("n", 10)
+		in.Define("f", float32(0.5))
+		fn, err := in.Eval(`fun(a, b) { return a * b + n + f; };`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := in.Call(fn, uint8(2), 3)
+		if err != nil || v != 16.5 {
+			t.Errorf("vm=%v: got %v %v", vm, v, err)
+		}
+	}
+}
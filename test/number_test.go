@@ -0,0 +1,49 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestIntegers(t *testing.T) {
+	src := `
+print 10 / 3; print 10 div 3; print -7 div 2; print -7 % 3; print 7.5 % 2;
+print 9007199254740993 + 0; print 1 + 0.5; print 1 == 1.0;
+var m = {1: "a"}; print m[1.0]; print [1, 2, 3][4 / 2];
+var x = 9; print x div 2; // a comment
+`
+	want := "3.3333333333333335\n3\n-4\n2\n1.5\n9007199254740993\n1.5\ntrue\na\n3\n4\n"
+	runBoth(t, src, want, lox.Options{})
+	for src, want := range map[string]string{
+		"9223372036854775807 + 1;": "[line 1] runtime error: integer overflow",
+		"1 % 0;":                   "[line 1] runtime error: modulo by zero",
+		`-"a";`:                    "[line 1] runtime error: operand must be a number",
+		`1 < "a";`:                 "[line 1] runtime error: right operand must be a number",
+	} {
+		failBoth(t, src, want, lox.Options{})
+	}
+	for _, vm := range []bool{false, true} {
+		in := lox.NewInterpreter(lox.Options{VM: vm})
+		if v, err := in.Eval("2 * 3;"); err != nil || v != int64(6) {
+			t.Errorf("vm=%v: got %T %v , %v", vm, v, v, err)
+		}
+	}
+}
+
+func TestOperators(t *testing.T) {
+	src := `
+print 2 ** 3 ** 2; print -2 ** 2; print 2 ** -1;
+print 6 & 3; print 6 | 3; print 6 ^ 3; print ~5; print 1 << 4; print -16 >> 2;
+print 1 | 2 ^ 3 & 4 << 1 + 1; print 5 & 1 == 1;
+`
+	want := "512\n-4\n0.5\n2\n7\n5\n-6\n16\n-4\n3\ntrue\n"
+	runBoth(t, src, want, lox.Options{})
+	for src, want := range map[string]string{
+		"1.5 & 1;": "[line 1] runtime error: left operand must be an integer",
+		"1 << -1;": "[line 1] runtime error: negative shift count",
+		"3 ** 40;": "[line 1] runtime error: integer overflow",
+	} {
+		failBoth(t, src, want, lox.Options{})
+	}
+}
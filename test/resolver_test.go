@@ -0,0 +1,32 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestResolverErrorsStopTheRun(t *testing.T) {
+	src := `
+print "before";
+return 1;
+{ var a = a; }
+while (true) { var f = fun() { break; }; }
+`
+	want := "[line 3] error at 'return': Can't return from top-level code.\n" +
+		"[line 4] error at 'a': Can't read local variable in its own initializer.\n" +
+		"[line 5] error at 'break': Can't use 'break' outside of a loop."
+	failBoth(t, src, want, lox.Options{})
+}
+
+func TestDiagnosticCodes(t *testing.T) {
+	in := lox.NewInterpreter(lox.Options{})
+	_, err := in.EvalFile("main.lox", "while (true) { fun f() { break; } }\nthis;")
+	ds := lox.Diagnostics(err)
+	if len(ds) != 2 || ds[0].Code != lox.S_BREAK_OUTSIDE_LOOP || ds[1].Code != lox.S_THIS_OUTSIDE_CLASS {
+		t.Fatalf("got %v", err)
+	}
+	if s := ds[0].Span; s.File != "main.lox" || s.Line != 1 || s.Column != 26 || len(ds[0].Notes) != 1 {
+		t.Errorf("got %+v", ds[0])
+	}
+}
@@ -1,6 +1,10 @@
 package test
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
 
 //sayHi("Dear", "Author")
 func TestFunctionCall(t *testing.T) {
@@ -10,3 +14,53 @@ func TestFunctionCall(t *testing.T) {
 //var a = 0
 
 //
+
+func TestScannerReportsAllErrors(t *testing.T) {
+	in := lox.NewInterpreter(lox.Options{})
+	_, err := in.Eval("var a = 1 @ 2;\nvar b = ;\nprint # \"x\";\nvar s = \"abc")
+	ds := lox.Diagnostics(err)
+	want := []string{lox.L_UNEXPECTED_CHAR, lox.P_EXPECTED_EXPRESSION, lox.L_UNEXPECTED_CHAR, lox.L_UNTERMINATED_STRING}
+	if len(ds) != len(want) {
+		t.Fatalf("got %v", err)
+	}
+	for i, d := range ds {
+		if d.Code != want[i] || d.Span.Line != i+1 {
+			t.Errorf("diagnostic %v: got %v %v , want %v on line %v", i, d.Code, d.Span.Line, want[i], i+1)
+		}
+	}
+}
+
+func TestNumberLiterals(t *testing.T) {
+	in := lox.NewInterpreter(lox.Options{})
+	v, err := in.Eval("0xFF + 0b1010 + 1_000_000 + 2.5e3 + 1E-1;")
+	if err != nil || v != 0xFF+0b1010+1_000_000+2.5e3+1e-1 {
+		t.Errorf("got %v , %v", v, err)
+	}
+
+	_, err = in.Eval("1.;\n0x;\n1_;\n0b12;\n1e+;")
+	ds := lox.Diagnostics(err)
+	if len(ds) != 5 {
+		t.Fatalf("got %v", err)
+	}
+	for i, d := range ds {
+		if d.Code != lox.L_MALFORMED_NUMBER || d.Span.Line != i+1 || d.Span.Column != 1 {
+			t.Errorf("got %+v", d)
+		}
+	}
+}
+
+func TestCommentsAfterValues(t *testing.T) {
+	src := `
+var x = 1;
+if (x == 1) // only when one
+  print x;
+fun f() // helper
+{ return [1,
+  2 // last
+]; }
+var n = 7 // a number
+;
+print f()[1] + n;
+`
+	runBoth(t, src, "1\n9\n", lox.Options{})
+}
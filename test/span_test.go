@@ -0,0 +1,36 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestFormatError(t *testing.T) {
+	for src, want := range map[string]string{
+		"var a = 1;\n\tvar b = \"héllo\" + ;": "[line 2] error at ';': expected expression\n" +
+			" --> main.lox:2:20\n" +
+			"  |\n" +
+			"2 | \tvar b = \"héllo\" + ;\n" +
+			"  | \t                  ^",
+		"(1 + 2) = 3;": "[line 1] error at '=': invalid assignment target\n" +
+			" --> main.lox:1:1\n" +
+			"  |\n" +
+			"1 | (1 + 2) = 3;\n" +
+			"  | ^~~~~~~\n" +
+			"  = note: only variables, properties and indexes can be assigned to",
+		"var xs = [1];\nprint \"é\" + xs[5];": "[line 2] runtime error: list index 5 out of range for length 1\n" +
+			" --> main.lox:2:15\n" +
+			"  |\n" +
+			"2 | print \"é\" + xs[5];\n" +
+			"  |               ^",
+	} {
+		evalBoth(t, "main.lox", src, lox.Options{}, func(vm bool, _ string, err error) {
+			if err == nil {
+				t.Errorf("vm=%v: %q ran without errors", vm, src)
+			} else if got := lox.FormatError(err, src); got != want {
+				t.Errorf("vm=%v: got\n%v\nwant\n%v", vm, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestStrings(t *testing.T) {
+	src := `
+var s = "héllo\t\"w\"\\\u{1F600}";
+print s;
+var größe = s.len();
+print größe;
+print s[1] + s[größe - 1];
+print """
+raw \n "quoted"
+""";
+`
+	want := "héllo\t\"w\"\\😀\n11\né😀\nraw \\n \"quoted\"\n\n"
+	runBoth(t, src, want, lox.Options{})
+	failBoth(t, `"héllo"[5];`, "[line 1] runtime error: string index 5 out of range for length 5", lox.Options{})
+
+	in := lox.NewInterpreter(lox.Options{})
+	_, err := in.Eval(`print "a\q \u{110000}";`)
+	if ds := lox.Diagnostics(err); len(ds) != 2 || ds[0].Code != lox.L_INVALID_ESCAPE || ds[1].Span.Column != 12 {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	src := `
+var name = "world";
+print "hello ${name}, ${1 + 2} \${x}";
+print "nested ${"in ${name + "!"}"} ${{"a": [1]}["a"][0]}";
+`
+	want := "hello world, 3 ${x}\nnested in world! 1\n"
+	runBoth(t, src, want, lox.Options{})
+}
+
+func TestStringify(t *testing.T) {
+	src := `
+fun f() {}
+class A { m() {} }
+print nil; print 1346269; print 2.5; print 1e20 * 10;
+print f; print fun() {}; print A; print A(); print A().m;
+print [1, nil, {"k": true}];
+print "n=" + 1 + nil; print str(3) + 4;
+var xs = [1]; xs.push(xs); var m = {"xs": xs}; m["m"] = m;
+print m; print [xs, xs];
+`
+	want := "nil\n1346269\n2.5\n1e+21\n<fn f>\n<fn>\n<class A>\nA instance\n<fn m>\n" +
+		"[1, nil, {k: true}]\nn=1nil\n34\n{xs: [1, [...]], m: {...}}\n[[1, [...]], [1, [...]]]\n"
+	runBoth(t, src, want, lox.Options{})
+	failBoth(t, `1 + nil;`, "[line 1] runtime error: operands must be two numbers or include a string", lox.Options{})
+	failBoth(t, `nil();`, "[line 1] runtime error: 'nil' is not a function or class", lox.Options{})
+	failBoth(t, `({})[nil];`, "[line 1] runtime error: key 'nil' not found in map", lox.Options{})
+}
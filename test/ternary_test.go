@@ -0,0 +1,19 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestTernary(t *testing.T) {
+	src := `
+fun boom() { print "boom"; return 0; }
+print true ? 1 : boom(); print false ? boom() : 2;
+var n = 5; print n > 3 ? "big" : n > 1 ? "mid" : "small";
+var x = n < 0 ? -1 : 1; print x;
+x = false ? 1 : 2; print x;
+`
+	want := "1\n2\nbig\n1\n2\n"
+	runBoth(t, src, want, lox.Options{})
+}
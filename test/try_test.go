@@ -0,0 +1,77 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/carlclone/golox/lox"
+)
+
+func TestTryCatch(t *testing.T) {
+	src := `
+fun f(n) {
+  try {
+    if (n == 0) return "zero";
+    throw "bad " + n;
+  } catch (e) {
+    return "caught " + e;
+  } finally {
+    print "finally " + n;
+  }
+}
+print f(0);
+print f(1);
+try { 1 / 0; } catch (e) { print e.message + " at " + e.line; }
+try { nope; } catch (e) { print e.message; }
+fun g(a) {}
+try { g(); } catch (e) { print e.message; }
+for (var i = 0; i < 5; i = i + 1) {
+  try {
+    if (i == 1) continue;
+    if (i == 3) break;
+    print "body " + i;
+  } finally {
+    print "fin " + i;
+  }
+}
+fun h() { try { return 1; } finally { return 2; } }
+print h();
+try {
+  try { throw {"code": 7}; } finally { print "inner finally"; }
+} catch (e) { print e["code"]; }
+try { try { [][0]; } catch (e) { throw e; } } catch (e) { print e.line; }
+`
+	want := "finally 0\nzero\nfinally 1\ncaught bad 1\ndivision by zero at 14\n" +
+		"undefined variable 'nope'\nexpected 1 arguments but got 0\n" +
+		"body 0\nfin 0\nfin 1\nbody 2\nfin 2\nfin 3\n2\ninner finally\n7\n32\n"
+	opts := lox.Options{MaxSteps: 1000}
+	runBoth(t, src, want, opts)
+	failBoth(t, `throw "boom";`, "[line 1] runtime error: uncaught exception: boom", opts)
+	// the step budget can't be caught , and the finally doesn't run
+	failBoth(t, `try { while (true) {} } catch (e) {} finally { print "no"; }`,
+		"[line 1] runtime error: step budget exceeded", opts)
+
+	in := lox.NewInterpreter(lox.Options{})
+	_, err := in.Eval(`try { }`)
+	if err == nil || err.Error() != "[line 1] error at end: expected 'catch' or 'finally' after try block" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestCatchThroughCallback(t *testing.T) {
+	src := `
+var x = 1;
+fun f() { var y = 2; try { apply(fun() { throw "boom"; }); } catch (e) { print "after " + e; } return x + y; }
+print f();
+`
+	for _, vm := range []bool{false, true} {
+		var out bytes.Buffer
+		in := lox.NewInterpreter(lox.Options{Stdout: &out, VM: vm})
+		if err := in.RegisterFunc("apply", func(fn lox.Value) (lox.Value, error) { return in.Call(fn) }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := in.Eval(src); err != nil || out.String() != "after boom\n3\n" {
+			t.Errorf("vm=%v: got %v , printed %q", vm, err, out.String())
+		}
+	}
+}